@@ -48,18 +48,27 @@ func (p *Program) String() string {
 
 // Statements
 type LetStatement struct {
-	Token token.Token // the token.LET token
+	Token token.Token // the token.LET token, or token.CONST for constants
 	Name  *Identifier
+	Type  *TypeAnnotation // nil when the binding is not annotated
 	Value Expression
+	Doc   string // text of the /// comments before the statement
 }
 
 func (ls *LetStatement) statementNode()       {}
 func (ls *LetStatement) TokenLiteral() string { return ls.Token.Literal }
+
+// IsConst reports whether the statement declares a constant with `const`.
+func (ls *LetStatement) IsConst() bool { return ls.Token.Type == token.CONST }
+
 func (ls *LetStatement) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(ls.TokenLiteral() + " ")
 	out.WriteString(ls.Name.String())
+	if ls.Type != nil {
+		out.WriteString(": " + ls.Type.String())
+	}
 	out.WriteString(" = ")
 
 	if ls.Value != nil {
@@ -123,6 +132,235 @@ func (bs *BlockStatement) String() string {
 	return out.String()
 }
 
+type ClassStatement struct {
+	Token      token.Token // the 'class' token
+	Name       *Identifier
+	Superclass *Identifier // nil unless the class extends another one
+	Methods    []*FunctionLiteral
+}
+
+func (cs *ClassStatement) statementNode()       {}
+func (cs *ClassStatement) TokenLiteral() string { return cs.Token.Literal }
+func (cs *ClassStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(cs.TokenLiteral() + " ")
+	out.WriteString(cs.Name.String())
+	if cs.Superclass != nil {
+		out.WriteString(" extends " + cs.Superclass.String())
+	}
+	out.WriteString(" { ")
+
+	for _, m := range cs.Methods {
+		out.WriteString(m.Name)
+		out.WriteString(m.parameters())
+		out.WriteString(" ")
+		out.WriteString(m.Body.String())
+		out.WriteString(" ")
+	}
+
+	out.WriteString("}")
+
+	return out.String()
+}
+
+type EnumStatement struct {
+	Token    token.Token // the 'enum' token
+	Name     *Identifier
+	Variants []*EnumVariant
+}
+
+// EnumVariant is one case of an enum declaration, e.g. Rect(w, h).
+type EnumVariant struct {
+	Name   *Identifier
+	Fields []*Identifier
+}
+
+func (ev *EnumVariant) TokenLiteral() string { return ev.Name.TokenLiteral() }
+func (ev *EnumVariant) String() string {
+	if len(ev.Fields) == 0 {
+		return ev.Name.String()
+	}
+
+	fields := []string{}
+	for _, f := range ev.Fields {
+		fields = append(fields, f.String())
+	}
+	return ev.Name.String() + "(" + strings.Join(fields, ", ") + ")"
+}
+
+func (es *EnumStatement) statementNode()       {}
+func (es *EnumStatement) TokenLiteral() string { return es.Token.Literal }
+func (es *EnumStatement) String() string {
+	var out bytes.Buffer
+
+	variants := []string{}
+	for _, v := range es.Variants {
+		variants = append(variants, v.String())
+	}
+
+	out.WriteString(es.TokenLiteral() + " ")
+	out.WriteString(es.Name.String())
+	out.WriteString(" { ")
+	out.WriteString(strings.Join(variants, ", "))
+	out.WriteString(" }")
+
+	return out.String()
+}
+
+type TryStatement struct {
+	Token      token.Token // the 'try' token
+	Block      *BlockStatement
+	CatchParam *Identifier // nil when the catch clause binds nothing
+	Catch      *BlockStatement
+	Finally    *BlockStatement
+}
+
+func (ts *TryStatement) statementNode()       {}
+func (ts *TryStatement) TokenLiteral() string { return ts.Token.Literal }
+func (ts *TryStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("try ")
+	out.WriteString(ts.Block.String())
+
+	if ts.Catch != nil {
+		out.WriteString(" catch ")
+		if ts.CatchParam != nil {
+			out.WriteString("(" + ts.CatchParam.String() + ") ")
+		}
+		out.WriteString(ts.Catch.String())
+	}
+
+	if ts.Finally != nil {
+		out.WriteString(" finally ")
+		out.WriteString(ts.Finally.String())
+	}
+
+	return out.String()
+}
+
+type ThrowStatement struct {
+	Token token.Token // the 'throw' token
+	Value Expression
+}
+
+func (ts *ThrowStatement) statementNode()       {}
+func (ts *ThrowStatement) TokenLiteral() string { return ts.Token.Literal }
+func (ts *ThrowStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ts.TokenLiteral() + " ")
+
+	if ts.Value != nil {
+		out.WriteString(ts.Value.String())
+	}
+
+	out.WriteString(";")
+
+	return out.String()
+}
+
+type DeferStatement struct {
+	Token      token.Token // the 'defer' token
+	Expression Expression
+}
+
+func (ds *DeferStatement) statementNode()       {}
+func (ds *DeferStatement) TokenLiteral() string { return ds.Token.Literal }
+func (ds *DeferStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ds.TokenLiteral() + " ")
+
+	if ds.Expression != nil {
+		out.WriteString(ds.Expression.String())
+	}
+
+	out.WriteString(";")
+
+	return out.String()
+}
+
+// ImportStatement binds the module at Path to Alias.
+type ImportStatement struct {
+	Token token.Token // the 'import' token
+	Path  *StringLiteral
+	Alias *Identifier
+}
+
+func (is *ImportStatement) statementNode()       {}
+func (is *ImportStatement) TokenLiteral() string { return is.Token.Literal }
+func (is *ImportStatement) String() string {
+	return is.TokenLiteral() + " \"" + is.Path.Value + "\" as " + is.Alias.String() + ";"
+}
+
+// ExportStatement makes the binding declared by Statement, a let, const,
+// class or enum statement, visible to modules that import this one.
+type ExportStatement struct {
+	Token     token.Token // the 'export' token
+	Statement Statement
+}
+
+func (es *ExportStatement) statementNode()       {}
+func (es *ExportStatement) TokenLiteral() string { return es.Token.Literal }
+func (es *ExportStatement) String() string {
+	return es.TokenLiteral() + " " + es.Statement.String()
+}
+
+// YieldStatement suspends a generator function and hands Value to the
+// code consuming the generator.
+type YieldStatement struct {
+	Token token.Token // the 'yield' token
+	Value Expression
+}
+
+func (ys *YieldStatement) statementNode()       {}
+func (ys *YieldStatement) TokenLiteral() string { return ys.Token.Literal }
+func (ys *YieldStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ys.TokenLiteral() + " ")
+
+	if ys.Value != nil {
+		out.WriteString(ys.Value.String())
+	}
+
+	out.WriteString(";")
+
+	return out.String()
+}
+
+// ForStatement loops over an iterable. With a single variable it binds
+// each element, or each key of a hash; `for (k, v) in x` binds the index or
+// key together with the element or value.
+type ForStatement struct {
+	Token    token.Token // the 'for' token
+	Key      *Identifier // nil in the single-variable form
+	Value    *Identifier
+	Iterable Expression
+	Body     *BlockStatement
+}
+
+func (fs *ForStatement) statementNode()       {}
+func (fs *ForStatement) TokenLiteral() string { return fs.Token.Literal }
+func (fs *ForStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for ")
+	if fs.Key != nil {
+		out.WriteString("(" + fs.Key.String() + ", " + fs.Value.String() + ")")
+	} else {
+		out.WriteString(fs.Value.String())
+	}
+	out.WriteString(" in ")
+	out.WriteString(fs.Iterable.String())
+	out.WriteString(" ")
+	out.WriteString(fs.Body.String())
+
+	return out.String()
+}
+
 // Expressions
 type Identifier struct {
 	Token token.Token // the token.IDENT token
@@ -217,26 +455,74 @@ func (ie *IfExpression) String() string {
 }
 
 type FunctionLiteral struct {
-	Token      token.Token // The 'fn' token
+	Token      token.Token // The 'fn' token, or the method name inside a class
+	Name       string      // set for class methods
 	Parameters []*Identifier
-	Body       *BlockStatement
+	// ParameterTypes is parallel to Parameters, with nil entries for
+	// parameters without annotation. It is nil when none is annotated.
+	ParameterTypes []*TypeAnnotation
+	ReturnType     *TypeAnnotation // nil when not annotated
+	Body           *BlockStatement
+	Generator      bool // set when the body contains a yield statement
 }
 
 func (fl *FunctionLiteral) expressionNode()      {}
 func (fl *FunctionLiteral) TokenLiteral() string { return fl.Token.Literal }
 func (fl *FunctionLiteral) String() string {
+	return fl.Signature() + " " + fl.Body.String()
+}
+
+// Signature renders the function without its body, such as
+// "fn(x: int, names: [string]) -> bool".
+func (fl *FunctionLiteral) Signature() string {
+	return fl.TokenLiteral() + fl.parameters()
+}
+
+// parameters renders the annotated parameter list and return type, which
+// follow "fn" in a literal and the method name in a class.
+func (fl *FunctionLiteral) parameters() string {
 	var out bytes.Buffer
 
 	params := []string{}
-	for _, p := range fl.Parameters {
+	for i, p := range fl.Parameters {
+		param := p.String()
+		if fl.ParameterTypes != nil && fl.ParameterTypes[i] != nil {
+			param += ": " + fl.ParameterTypes[i].String()
+		}
+		params = append(params, param)
+	}
+
+	out.WriteString("(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(")")
+	if fl.ReturnType != nil {
+		out.WriteString(" -> " + fl.ReturnType.String())
+	}
+
+	return out.String()
+}
+
+type MacroLiteral struct {
+	Token      token.Token // The 'macro' token
+	Parameters []*Identifier
+	Body       *BlockStatement
+}
+
+func (ml *MacroLiteral) expressionNode()      {}
+func (ml *MacroLiteral) TokenLiteral() string { return ml.Token.Literal }
+func (ml *MacroLiteral) String() string {
+	var out bytes.Buffer
+
+	params := []string{}
+	for _, p := range ml.Parameters {
 		params = append(params, p.String())
 	}
 
-	out.WriteString(fl.TokenLiteral())
+	out.WriteString(ml.TokenLiteral())
 	out.WriteString("(")
 	out.WriteString(strings.Join(params, ", "))
 	out.WriteString(") ")
-	out.WriteString(fl.Body.String())
+	out.WriteString(ml.Body.String())
 
 	return out.String()
 }
@@ -316,9 +602,47 @@ func (ie *IndexExpression) String() string {
 	return out.String()
 }
 
+type SliceExpression struct {
+	Token token.Token // The [ token
+	Left  Expression
+	Start Expression // nil when omitted
+	End   Expression // nil when omitted
+	Step  Expression // nil when omitted
+}
+
+func (se *SliceExpression) expressionNode()      {}
+func (se *SliceExpression) TokenLiteral() string { return se.Token.Literal }
+func (se *SliceExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(se.Left.String())
+	out.WriteString("[")
+	if se.Start != nil {
+		out.WriteString(se.Start.String())
+	}
+	out.WriteString(":")
+	if se.End != nil {
+		out.WriteString(se.End.String())
+	}
+	if se.Step != nil {
+		out.WriteString(":")
+		out.WriteString(se.Step.String())
+	}
+	out.WriteString("])")
+
+	return out.String()
+}
+
 type HashLiteral struct {
 	Token token.Token // the '{' token
-	Pairs map[Expression]Expression
+	Pairs []HashPair  // in source order
+}
+
+// HashPair is one `key: value` entry of a hash literal.
+type HashPair struct {
+	Key   Expression
+	Value Expression
 }
 
 func (hl *HashLiteral) expressionNode()      {}
@@ -327,8 +651,8 @@ func (hl *HashLiteral) String() string {
 	var out bytes.Buffer
 
 	pairs := []string{}
-	for key, value := range hl.Pairs {
-		pairs = append(pairs, key.String()+":"+value.String())
+	for _, pair := range hl.Pairs {
+		pairs = append(pairs, pair.Key.String()+":"+pair.Value.String())
 	}
 
 	out.WriteString("{")
@@ -337,3 +661,131 @@ func (hl *HashLiteral) String() string {
 
 	return out.String()
 }
+
+type MemberExpression struct {
+	Token    token.Token // The '.' token
+	Object   Expression
+	Property *Identifier
+}
+
+func (me *MemberExpression) expressionNode()      {}
+func (me *MemberExpression) TokenLiteral() string { return me.Token.Literal }
+func (me *MemberExpression) String() string {
+	return me.Object.String() + "." + me.Property.String()
+}
+
+type AssignExpression struct {
+	Token    token.Token // The assignment token, e.g. = or +=
+	Target   Expression  // Identifier, MemberExpression or IndexExpression
+	Operator string
+	Value    Expression
+}
+
+func (ae *AssignExpression) expressionNode()      {}
+func (ae *AssignExpression) TokenLiteral() string { return ae.Token.Literal }
+func (ae *AssignExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("(")
+	out.WriteString(ae.Target.String())
+	out.WriteString(" " + ae.Operator + " ")
+	out.WriteString(ae.Value.String())
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// RangeExpression is `start..end`, or `start..=end` when the end is
+// included.
+type RangeExpression struct {
+	Token     token.Token // the '..' or '..=' token
+	Start     Expression
+	End       Expression
+	Inclusive bool
+}
+
+func (re *RangeExpression) expressionNode()      {}
+func (re *RangeExpression) TokenLiteral() string { return re.Token.Literal }
+func (re *RangeExpression) String() string {
+	return "(" + re.Start.String() + re.Token.Literal + re.End.String() + ")"
+}
+
+// ComprehensionClause is the `for x in xs if cond` part of an array or
+// hash comprehension.
+type ComprehensionClause struct {
+	Token     token.Token // the 'for' token
+	Key       *Identifier // nil in the single-variable form
+	Value     *Identifier
+	Iterable  Expression
+	Condition Expression // nil when there is no `if` filter
+}
+
+func (cc *ComprehensionClause) TokenLiteral() string { return cc.Token.Literal }
+func (cc *ComprehensionClause) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("for ")
+	if cc.Key != nil {
+		out.WriteString("(" + cc.Key.String() + ", " + cc.Value.String() + ")")
+	} else {
+		out.WriteString(cc.Value.String())
+	}
+	out.WriteString(" in ")
+	out.WriteString(cc.Iterable.String())
+
+	if cc.Condition != nil {
+		out.WriteString(" if ")
+		out.WriteString(cc.Condition.String())
+	}
+
+	return out.String()
+}
+
+// ArrayComprehension is `[element for x in xs if cond]`.
+type ArrayComprehension struct {
+	Token   token.Token // the '[' token
+	Element Expression
+	Clause  *ComprehensionClause
+}
+
+func (ac *ArrayComprehension) expressionNode()      {}
+func (ac *ArrayComprehension) TokenLiteral() string { return ac.Token.Literal }
+func (ac *ArrayComprehension) String() string {
+	return "[" + ac.Element.String() + " " + ac.Clause.String() + "]"
+}
+
+// HashComprehension is `{key: value for (k, v) in h if cond}`.
+type HashComprehension struct {
+	Token  token.Token // the '{' token
+	Key    Expression
+	Value  Expression
+	Clause *ComprehensionClause
+}
+
+func (hc *HashComprehension) expressionNode()      {}
+func (hc *HashComprehension) TokenLiteral() string { return hc.Token.Literal }
+func (hc *HashComprehension) String() string {
+	return "{" + hc.Key.String() + ":" + hc.Value.String() + " " + hc.Clause.String() + "}"
+}
+
+// TypeAnnotation is a type written after `:` or `->`. It is a name such as
+// int, string or a class name, an array type [Elem], or a hash type
+// {Key: Elem}.
+type TypeAnnotation struct {
+	Token token.Token     // the first token of the type
+	Name  string          // empty for array and hash types
+	Key   *TypeAnnotation // key type of a hash type
+	Elem  *TypeAnnotation // element type of an array type, value type of a hash type
+}
+
+func (ta *TypeAnnotation) TokenLiteral() string { return ta.Token.Literal }
+func (ta *TypeAnnotation) String() string {
+	switch {
+	case ta.Key != nil:
+		return "{" + ta.Key.String() + ": " + ta.Elem.String() + "}"
+	case ta.Elem != nil:
+		return "[" + ta.Elem.String() + "]"
+	default:
+		return ta.Name
+	}
+}
@@ -0,0 +1,876 @@
+package ast
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/Devashish08/InterPreter-Compiler/token"
+)
+
+// JSONVersion is the version of the JSON schema below. It is written to
+// the "version" field of every Program and changes only when the schema
+// changes incompatibly.
+//
+// Every node is a JSON object. Its "kind" is the name of its Go type, such
+// as "InfixExpression". Nodes that have a token also carry
+//
+//	"token":  {"type": "+", "literal": "+"}
+//	"line":   1-based line of the token
+//	"column": 1-based column of the token
+//
+// The remaining fields are the node's Go struct fields in lowerCamelCase,
+// always present and in the order listed here. Child nodes are nested
+// objects, lists are arrays, and absent optional children are null:
+//
+//	Program             version, statements
+//	LetStatement        name, type, value, doc
+//	ReturnStatement     returnValue
+//	ExpressionStatement expression
+//	BlockStatement      statements
+//	ClassStatement      name, superclass, methods
+//	EnumStatement       name, variants
+//	EnumVariant         name, fields (no token or position)
+//	TryStatement        block, catchParam, catch, finally
+//	ThrowStatement      value
+//	DeferStatement      expression
+//	ImportStatement     path, alias
+//	ExportStatement     statement
+//	YieldStatement      value
+//	ForStatement        key, value, iterable, body
+//	Identifier          value
+//	Boolean             value
+//	IntegerLiteral      value
+//	StringLiteral       value
+//	PrefixExpression    operator, right
+//	InfixExpression     left, operator, right
+//	IfExpression        condition, consequence, alternative
+//	FunctionLiteral     name, parameters, parameterTypes, returnType, body, generator
+//	MacroLiteral        parameters, body
+//	CallExpression      function, arguments
+//	ArrayLiteral        elements
+//	IndexExpression     left, index
+//	SliceExpression     left, start, end, step
+//	HashLiteral         pairs, an array of {"key": node, "value": node} in source order
+//	MemberExpression    object, property
+//	AssignExpression    target, operator, value
+//	RangeExpression     start, end, inclusive
+//	ComprehensionClause key, value, iterable, condition
+//	ArrayComprehension  element, clause
+//	HashComprehension   key, value, clause
+//	TypeAnnotation      name, key, elem
+const JSONVersion = 1
+
+// MarshalNode encodes node and its children following the schema
+// documented at JSONVersion.
+func MarshalNode(node Node) ([]byte, error) {
+	return json.Marshal(encodeNode(node))
+}
+
+// UnmarshalNode decodes a node encoded by MarshalNode. The result is equal
+// to the encoded tree, positions included. A child of a kind its field
+// cannot hold, such as an IntegerLiteral as a let statement's name, is an
+// error.
+func UnmarshalNode(data []byte) (Node, error) {
+	return decodeNode(data)
+}
+
+func (p *Program) MarshalJSON() ([]byte, error) {
+	return MarshalNode(p)
+}
+
+func (p *Program) UnmarshalJSON(data []byte) error {
+	node, err := decodeNode(data)
+	if err != nil {
+		return err
+	}
+
+	program, ok := node.(*Program)
+	if !ok {
+		return fmt.Errorf("expected a Program, got %T", node)
+	}
+
+	*p = *program
+	return nil
+}
+
+// jsonObject is a JSON object that keeps its fields in order.
+type jsonObject []jsonField
+
+type jsonField struct {
+	key   string
+	value interface{}
+}
+
+func (o jsonObject) MarshalJSON() ([]byte, error) {
+	var out bytes.Buffer
+
+	out.WriteString("{")
+	for i, field := range o {
+		if i > 0 {
+			out.WriteString(",")
+		}
+
+		key, err := json.Marshal(field.key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(field.value)
+		if err != nil {
+			return nil, err
+		}
+
+		out.Write(key)
+		out.WriteString(":")
+		out.Write(value)
+	}
+	out.WriteString("}")
+
+	return out.Bytes(), nil
+}
+
+// newJSONObject starts the object for a node with its kind and token.
+func newJSONObject(kind string, tok token.Token, fields ...jsonField) jsonObject {
+	obj := jsonObject{
+		{"kind", kind},
+		{"token", jsonObject{{"type", string(tok.Type)}, {"literal", tok.Literal}}},
+		{"line", tok.Line},
+		{"column", tok.Column},
+	}
+	return append(obj, fields...)
+}
+
+// encodeNode converts node to the value that is marshalled for it, nil for
+// absent nodes.
+func encodeNode(node Node) interface{} {
+	if node == nil || reflect.ValueOf(node).IsNil() {
+		return nil
+	}
+
+	switch n := node.(type) {
+	case *Program:
+		return jsonObject{
+			{"kind", "Program"},
+			{"version", JSONVersion},
+			{"statements", encodeStatements(n.Statements)},
+		}
+
+	case *LetStatement:
+		return newJSONObject("LetStatement", n.Token,
+			jsonField{"name", encodeNode(n.Name)},
+			jsonField{"type", encodeNode(n.Type)},
+			jsonField{"value", encodeNode(n.Value)},
+			jsonField{"doc", n.Doc})
+
+	case *ReturnStatement:
+		return newJSONObject("ReturnStatement", n.Token,
+			jsonField{"returnValue", encodeNode(n.ReturnValue)})
+
+	case *ExpressionStatement:
+		return newJSONObject("ExpressionStatement", n.Token,
+			jsonField{"expression", encodeNode(n.Expression)})
+
+	case *BlockStatement:
+		return newJSONObject("BlockStatement", n.Token,
+			jsonField{"statements", encodeStatements(n.Statements)})
+
+	case *ClassStatement:
+		var methods []interface{}
+		if n.Methods != nil {
+			methods = []interface{}{}
+			for _, method := range n.Methods {
+				methods = append(methods, encodeNode(method))
+			}
+		}
+		return newJSONObject("ClassStatement", n.Token,
+			jsonField{"name", encodeNode(n.Name)},
+			jsonField{"superclass", encodeNode(n.Superclass)},
+			jsonField{"methods", methods})
+
+	case *EnumStatement:
+		var variants []interface{}
+		if n.Variants != nil {
+			variants = []interface{}{}
+			for _, variant := range n.Variants {
+				variants = append(variants, encodeNode(variant))
+			}
+		}
+		return newJSONObject("EnumStatement", n.Token,
+			jsonField{"name", encodeNode(n.Name)},
+			jsonField{"variants", variants})
+
+	case *EnumVariant:
+		return jsonObject{
+			{"kind", "EnumVariant"},
+			{"name", encodeNode(n.Name)},
+			{"fields", encodeIdentifiers(n.Fields)},
+		}
+
+	case *TryStatement:
+		return newJSONObject("TryStatement", n.Token,
+			jsonField{"block", encodeNode(n.Block)},
+			jsonField{"catchParam", encodeNode(n.CatchParam)},
+			jsonField{"catch", encodeNode(n.Catch)},
+			jsonField{"finally", encodeNode(n.Finally)})
+
+	case *ThrowStatement:
+		return newJSONObject("ThrowStatement", n.Token,
+			jsonField{"value", encodeNode(n.Value)})
+
+	case *DeferStatement:
+		return newJSONObject("DeferStatement", n.Token,
+			jsonField{"expression", encodeNode(n.Expression)})
+
+	case *ImportStatement:
+		return newJSONObject("ImportStatement", n.Token,
+			jsonField{"path", encodeNode(n.Path)},
+			jsonField{"alias", encodeNode(n.Alias)})
+
+	case *ExportStatement:
+		return newJSONObject("ExportStatement", n.Token,
+			jsonField{"statement", encodeNode(n.Statement)})
+
+	case *YieldStatement:
+		return newJSONObject("YieldStatement", n.Token,
+			jsonField{"value", encodeNode(n.Value)})
+
+	case *ForStatement:
+		return newJSONObject("ForStatement", n.Token,
+			jsonField{"key", encodeNode(n.Key)},
+			jsonField{"value", encodeNode(n.Value)},
+			jsonField{"iterable", encodeNode(n.Iterable)},
+			jsonField{"body", encodeNode(n.Body)})
+
+	case *Identifier:
+		return newJSONObject("Identifier", n.Token, jsonField{"value", n.Value})
+
+	case *Boolean:
+		return newJSONObject("Boolean", n.Token, jsonField{"value", n.Value})
+
+	case *IntegerLiteral:
+		return newJSONObject("IntegerLiteral", n.Token, jsonField{"value", n.Value})
+
+	case *StringLiteral:
+		return newJSONObject("StringLiteral", n.Token, jsonField{"value", n.Value})
+
+	case *PrefixExpression:
+		return newJSONObject("PrefixExpression", n.Token,
+			jsonField{"operator", n.Operator},
+			jsonField{"right", encodeNode(n.Right)})
+
+	case *InfixExpression:
+		return newJSONObject("InfixExpression", n.Token,
+			jsonField{"left", encodeNode(n.Left)},
+			jsonField{"operator", n.Operator},
+			jsonField{"right", encodeNode(n.Right)})
+
+	case *IfExpression:
+		return newJSONObject("IfExpression", n.Token,
+			jsonField{"condition", encodeNode(n.Condition)},
+			jsonField{"consequence", encodeNode(n.Consequence)},
+			jsonField{"alternative", encodeNode(n.Alternative)})
+
+	case *FunctionLiteral:
+		var types []interface{}
+		if n.ParameterTypes != nil {
+			types = []interface{}{}
+			for _, typ := range n.ParameterTypes {
+				types = append(types, encodeNode(typ))
+			}
+		}
+		return newJSONObject("FunctionLiteral", n.Token,
+			jsonField{"name", n.Name},
+			jsonField{"parameters", encodeIdentifiers(n.Parameters)},
+			jsonField{"parameterTypes", types},
+			jsonField{"returnType", encodeNode(n.ReturnType)},
+			jsonField{"body", encodeNode(n.Body)},
+			jsonField{"generator", n.Generator})
+
+	case *MacroLiteral:
+		return newJSONObject("MacroLiteral", n.Token,
+			jsonField{"parameters", encodeIdentifiers(n.Parameters)},
+			jsonField{"body", encodeNode(n.Body)})
+
+	case *CallExpression:
+		return newJSONObject("CallExpression", n.Token,
+			jsonField{"function", encodeNode(n.Function)},
+			jsonField{"arguments", encodeExpressions(n.Arguments)})
+
+	case *ArrayLiteral:
+		return newJSONObject("ArrayLiteral", n.Token,
+			jsonField{"elements", encodeExpressions(n.Elements)})
+
+	case *IndexExpression:
+		return newJSONObject("IndexExpression", n.Token,
+			jsonField{"left", encodeNode(n.Left)},
+			jsonField{"index", encodeNode(n.Index)})
+
+	case *SliceExpression:
+		return newJSONObject("SliceExpression", n.Token,
+			jsonField{"left", encodeNode(n.Left)},
+			jsonField{"start", encodeNode(n.Start)},
+			jsonField{"end", encodeNode(n.End)},
+			jsonField{"step", encodeNode(n.Step)})
+
+	case *HashLiteral:
+		var pairs []interface{}
+		if n.Pairs != nil {
+			pairs = []interface{}{}
+			for _, pair := range n.Pairs {
+				pairs = append(pairs, jsonObject{
+					{"key", encodeNode(pair.Key)},
+					{"value", encodeNode(pair.Value)},
+				})
+			}
+		}
+		return newJSONObject("HashLiteral", n.Token, jsonField{"pairs", pairs})
+
+	case *MemberExpression:
+		return newJSONObject("MemberExpression", n.Token,
+			jsonField{"object", encodeNode(n.Object)},
+			jsonField{"property", encodeNode(n.Property)})
+
+	case *AssignExpression:
+		return newJSONObject("AssignExpression", n.Token,
+			jsonField{"target", encodeNode(n.Target)},
+			jsonField{"operator", n.Operator},
+			jsonField{"value", encodeNode(n.Value)})
+
+	case *RangeExpression:
+		return newJSONObject("RangeExpression", n.Token,
+			jsonField{"start", encodeNode(n.Start)},
+			jsonField{"end", encodeNode(n.End)},
+			jsonField{"inclusive", n.Inclusive})
+
+	case *ComprehensionClause:
+		return newJSONObject("ComprehensionClause", n.Token,
+			jsonField{"key", encodeNode(n.Key)},
+			jsonField{"value", encodeNode(n.Value)},
+			jsonField{"iterable", encodeNode(n.Iterable)},
+			jsonField{"condition", encodeNode(n.Condition)})
+
+	case *ArrayComprehension:
+		return newJSONObject("ArrayComprehension", n.Token,
+			jsonField{"element", encodeNode(n.Element)},
+			jsonField{"clause", encodeNode(n.Clause)})
+
+	case *HashComprehension:
+		return newJSONObject("HashComprehension", n.Token,
+			jsonField{"key", encodeNode(n.Key)},
+			jsonField{"value", encodeNode(n.Value)},
+			jsonField{"clause", encodeNode(n.Clause)})
+
+	case *TypeAnnotation:
+		return newJSONObject("TypeAnnotation", n.Token,
+			jsonField{"name", n.Name},
+			jsonField{"key", encodeNode(n.Key)},
+			jsonField{"elem", encodeNode(n.Elem)})
+	}
+
+	panic(fmt.Sprintf("ast: cannot encode %T", node))
+}
+
+// The encode helpers below keep the difference between nil and empty
+// lists, so decoding gives back exactly the encoded tree.
+
+func encodeStatements(statements []Statement) []interface{} {
+	if statements == nil {
+		return nil
+	}
+
+	encoded := []interface{}{}
+	for _, stmt := range statements {
+		encoded = append(encoded, encodeNode(stmt))
+	}
+	return encoded
+}
+
+func encodeExpressions(expressions []Expression) []interface{} {
+	if expressions == nil {
+		return nil
+	}
+
+	encoded := []interface{}{}
+	for _, expr := range expressions {
+		encoded = append(encoded, encodeNode(expr))
+	}
+	return encoded
+}
+
+func encodeIdentifiers(identifiers []*Identifier) []interface{} {
+	if identifiers == nil {
+		return nil
+	}
+
+	encoded := []interface{}{}
+	for _, ident := range identifiers {
+		encoded = append(encoded, encodeNode(ident))
+	}
+	return encoded
+}
+
+// jsonDecoder decodes the fields of one node. The first error is kept and
+// later calls do nothing, so node constructors can decode all their
+// fields and check for an error once.
+type jsonDecoder struct {
+	fields map[string]json.RawMessage
+	err    error
+}
+
+func decodeNode(data []byte) (Node, error) {
+	if isNull(data) {
+		return nil, nil
+	}
+
+	d := &jsonDecoder{}
+	if err := json.Unmarshal(data, &d.fields); err != nil {
+		return nil, err
+	}
+
+	var kind string
+	d.value("kind", &kind)
+	if d.err != nil {
+		return nil, d.err
+	}
+
+	node := d.node(kind)
+	if d.err != nil {
+		return nil, fmt.Errorf("%s: %s", kind, d.err)
+	}
+	return node, nil
+}
+
+func (d *jsonDecoder) node(kind string) Node {
+	switch kind {
+	case "Program":
+		var version int
+		d.value("version", &version)
+		if d.err == nil && version != JSONVersion {
+			d.err = fmt.Errorf("unsupported version %d, want %d", version, JSONVersion)
+		}
+		return &Program{Statements: d.statements("statements")}
+
+	case "LetStatement":
+		n := &LetStatement{Token: d.token()}
+		n.Name = d.identifier("name")
+		n.Type = d.typeAnnotation("type")
+		n.Value = d.expression("value")
+		d.value("doc", &n.Doc)
+		return n
+
+	case "ReturnStatement":
+		return &ReturnStatement{Token: d.token(), ReturnValue: d.expression("returnValue")}
+
+	case "ExpressionStatement":
+		return &ExpressionStatement{Token: d.token(), Expression: d.expression("expression")}
+
+	case "BlockStatement":
+		return &BlockStatement{Token: d.token(), Statements: d.statements("statements")}
+
+	case "ClassStatement":
+		n := &ClassStatement{Token: d.token()}
+		n.Name = d.identifier("name")
+		n.Superclass = d.identifier("superclass")
+		d.list("methods", func(isNil bool) {
+			if !isNil {
+				n.Methods = []*FunctionLiteral{}
+			}
+		}, func(data json.RawMessage) {
+			node := d.decode(data)
+			method, ok := node.(*FunctionLiteral)
+			d.expect("methods", node, ok, "FunctionLiteral")
+			n.Methods = append(n.Methods, method)
+		})
+		return n
+
+	case "EnumStatement":
+		n := &EnumStatement{Token: d.token()}
+		n.Name = d.identifier("name")
+		d.list("variants", func(isNil bool) {
+			if !isNil {
+				n.Variants = []*EnumVariant{}
+			}
+		}, func(data json.RawMessage) {
+			node := d.decode(data)
+			variant, ok := node.(*EnumVariant)
+			d.expect("variants", node, ok, "EnumVariant")
+			n.Variants = append(n.Variants, variant)
+		})
+		return n
+
+	case "EnumVariant":
+		return &EnumVariant{Name: d.identifier("name"), Fields: d.identifiers("fields")}
+
+	case "TryStatement":
+		n := &TryStatement{Token: d.token()}
+		n.Block = d.block("block")
+		n.CatchParam = d.identifier("catchParam")
+		n.Catch = d.block("catch")
+		n.Finally = d.block("finally")
+		return n
+
+	case "ThrowStatement":
+		return &ThrowStatement{Token: d.token(), Value: d.expression("value")}
+
+	case "DeferStatement":
+		return &DeferStatement{Token: d.token(), Expression: d.expression("expression")}
+
+	case "ImportStatement":
+		n := &ImportStatement{Token: d.token()}
+		node := d.field("path")
+		path, ok := node.(*StringLiteral)
+		d.expect("path", node, ok, "StringLiteral")
+		n.Path = path
+		n.Alias = d.identifier("alias")
+		return n
+
+	case "ExportStatement":
+		n := &ExportStatement{Token: d.token()}
+		node := d.field("statement")
+		stmt, ok := node.(Statement)
+		d.expect("statement", node, ok, "a statement")
+		n.Statement = stmt
+		return n
+
+	case "YieldStatement":
+		return &YieldStatement{Token: d.token(), Value: d.expression("value")}
+
+	case "ForStatement":
+		n := &ForStatement{Token: d.token()}
+		n.Key = d.identifier("key")
+		n.Value = d.identifier("value")
+		n.Iterable = d.expression("iterable")
+		n.Body = d.block("body")
+		return n
+
+	case "Identifier":
+		n := &Identifier{Token: d.token()}
+		d.value("value", &n.Value)
+		return n
+
+	case "Boolean":
+		n := &Boolean{Token: d.token()}
+		d.value("value", &n.Value)
+		return n
+
+	case "IntegerLiteral":
+		n := &IntegerLiteral{Token: d.token()}
+		d.value("value", &n.Value)
+		return n
+
+	case "StringLiteral":
+		n := &StringLiteral{Token: d.token()}
+		d.value("value", &n.Value)
+		return n
+
+	case "PrefixExpression":
+		n := &PrefixExpression{Token: d.token()}
+		d.value("operator", &n.Operator)
+		n.Right = d.expression("right")
+		return n
+
+	case "InfixExpression":
+		n := &InfixExpression{Token: d.token()}
+		n.Left = d.expression("left")
+		d.value("operator", &n.Operator)
+		n.Right = d.expression("right")
+		return n
+
+	case "IfExpression":
+		n := &IfExpression{Token: d.token()}
+		n.Condition = d.expression("condition")
+		n.Consequence = d.block("consequence")
+		n.Alternative = d.block("alternative")
+		return n
+
+	case "FunctionLiteral":
+		n := &FunctionLiteral{Token: d.token()}
+		d.value("name", &n.Name)
+		n.Parameters = d.identifiers("parameters")
+		d.list("parameterTypes", func(isNil bool) {
+			if !isNil {
+				n.ParameterTypes = []*TypeAnnotation{}
+			}
+		}, func(data json.RawMessage) {
+			node := d.decode(data)
+			typ, ok := node.(*TypeAnnotation)
+			d.expect("parameterTypes", node, ok, "TypeAnnotation")
+			n.ParameterTypes = append(n.ParameterTypes, typ)
+		})
+		n.ReturnType = d.typeAnnotation("returnType")
+		n.Body = d.block("body")
+		d.value("generator", &n.Generator)
+		return n
+
+	case "MacroLiteral":
+		n := &MacroLiteral{Token: d.token()}
+		n.Parameters = d.identifiers("parameters")
+		n.Body = d.block("body")
+		return n
+
+	case "CallExpression":
+		n := &CallExpression{Token: d.token()}
+		n.Function = d.expression("function")
+		n.Arguments = d.expressions("arguments")
+		return n
+
+	case "ArrayLiteral":
+		return &ArrayLiteral{Token: d.token(), Elements: d.expressions("elements")}
+
+	case "IndexExpression":
+		n := &IndexExpression{Token: d.token()}
+		n.Left = d.expression("left")
+		n.Index = d.expression("index")
+		return n
+
+	case "SliceExpression":
+		n := &SliceExpression{Token: d.token()}
+		n.Left = d.expression("left")
+		n.Start = d.expression("start")
+		n.End = d.expression("end")
+		n.Step = d.expression("step")
+		return n
+
+	case "HashLiteral":
+		n := &HashLiteral{Token: d.token()}
+		d.list("pairs", func(isNil bool) {
+			if !isNil {
+				n.Pairs = []HashPair{}
+			}
+		}, func(data json.RawMessage) {
+			pair := &jsonDecoder{}
+			if err := json.Unmarshal(data, &pair.fields); err != nil {
+				d.fail(err)
+				return
+			}
+			key := pair.expression("key")
+			value := pair.expression("value")
+			d.fail(pair.err)
+			n.Pairs = append(n.Pairs, HashPair{Key: key, Value: value})
+		})
+		return n
+
+	case "MemberExpression":
+		n := &MemberExpression{Token: d.token()}
+		n.Object = d.expression("object")
+		n.Property = d.identifier("property")
+		return n
+
+	case "AssignExpression":
+		n := &AssignExpression{Token: d.token()}
+		n.Target = d.expression("target")
+		d.value("operator", &n.Operator)
+		n.Value = d.expression("value")
+		return n
+
+	case "RangeExpression":
+		n := &RangeExpression{Token: d.token()}
+		n.Start = d.expression("start")
+		n.End = d.expression("end")
+		d.value("inclusive", &n.Inclusive)
+		return n
+
+	case "ComprehensionClause":
+		n := &ComprehensionClause{Token: d.token()}
+		n.Key = d.identifier("key")
+		n.Value = d.identifier("value")
+		n.Iterable = d.expression("iterable")
+		n.Condition = d.expression("condition")
+		return n
+
+	case "ArrayComprehension":
+		n := &ArrayComprehension{Token: d.token()}
+		n.Element = d.expression("element")
+		n.Clause = d.clause("clause")
+		return n
+
+	case "HashComprehension":
+		n := &HashComprehension{Token: d.token()}
+		n.Key = d.expression("key")
+		n.Value = d.expression("value")
+		n.Clause = d.clause("clause")
+		return n
+
+	case "TypeAnnotation":
+		n := &TypeAnnotation{Token: d.token()}
+		d.value("name", &n.Name)
+		n.Key = d.typeAnnotation("key")
+		n.Elem = d.typeAnnotation("elem")
+		return n
+	}
+
+	d.fail(fmt.Errorf("unknown node kind %q", kind))
+	return nil
+}
+
+func (d *jsonDecoder) fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+// value unmarshals a plain field into ptr.
+func (d *jsonDecoder) value(key string, ptr interface{}) {
+	data, ok := d.fields[key]
+	if !ok {
+		d.fail(fmt.Errorf("missing field %q", key))
+		return
+	}
+	if err := json.Unmarshal(data, ptr); err != nil {
+		d.fail(fmt.Errorf("field %q: %s", key, err))
+	}
+}
+
+func (d *jsonDecoder) token() token.Token {
+	var tok struct {
+		Type    string `json:"type"`
+		Literal string `json:"literal"`
+	}
+	d.value("token", &tok)
+
+	t := token.Token{Type: token.TokenType(tok.Type), Literal: tok.Literal}
+	d.value("line", &t.Line)
+	d.value("column", &t.Column)
+	return t
+}
+
+func (d *jsonDecoder) decode(data json.RawMessage) Node {
+	if d.err != nil {
+		return nil
+	}
+
+	node, err := decodeNode(data)
+	d.fail(err)
+	return node
+}
+
+// field decodes the child node stored under key.
+func (d *jsonDecoder) field(key string) Node {
+	data, ok := d.fields[key]
+	if !ok {
+		d.fail(fmt.Errorf("missing field %q", key))
+		return nil
+	}
+	return d.decode(data)
+}
+
+func (d *jsonDecoder) expression(key string) Expression {
+	node := d.field(key)
+	if node == nil {
+		return nil
+	}
+
+	expr, ok := node.(Expression)
+	d.expect(key, node, ok, "an expression")
+	return expr
+}
+
+func (d *jsonDecoder) identifier(key string) *Identifier {
+	node := d.field(key)
+	ident, ok := node.(*Identifier)
+	d.expect(key, node, ok, "Identifier")
+	return ident
+}
+
+func (d *jsonDecoder) block(key string) *BlockStatement {
+	node := d.field(key)
+	block, ok := node.(*BlockStatement)
+	d.expect(key, node, ok, "BlockStatement")
+	return block
+}
+
+func (d *jsonDecoder) typeAnnotation(key string) *TypeAnnotation {
+	node := d.field(key)
+	typ, ok := node.(*TypeAnnotation)
+	d.expect(key, node, ok, "TypeAnnotation")
+	return typ
+}
+
+func (d *jsonDecoder) clause(key string) *ComprehensionClause {
+	node := d.field(key)
+	clause, ok := node.(*ComprehensionClause)
+	d.expect(key, node, ok, "ComprehensionClause")
+	return clause
+}
+
+// expect fails decoding when node, decoded from the field key, is not of
+// the wanted kind, which ok reports. Null children are left to the caller.
+func (d *jsonDecoder) expect(key string, node Node, ok bool, want string) {
+	if node != nil && !ok {
+		d.fail(fmt.Errorf("field %q: expected %s, got %s", key, want, kindOf(node)))
+	}
+}
+
+// kindOf returns the kind node is encoded with: the name of its Go type.
+func kindOf(node Node) string {
+	t := reflect.TypeOf(node)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Name()
+}
+
+// list decodes the array stored under key. start is told whether the
+// array is null, then each calls elem for each element.
+func (d *jsonDecoder) list(key string, start func(isNil bool), elem func(json.RawMessage)) {
+	var elements []json.RawMessage
+	d.value(key, &elements)
+	if d.err != nil {
+		return
+	}
+
+	start(elements == nil)
+	for _, data := range elements {
+		elem(data)
+	}
+}
+
+func (d *jsonDecoder) statements(key string) []Statement {
+	var statements []Statement
+	d.list(key, func(isNil bool) {
+		if !isNil {
+			statements = []Statement{}
+		}
+	}, func(data json.RawMessage) {
+		node := d.decode(data)
+		stmt, ok := node.(Statement)
+		d.expect(key, node, ok, "a statement")
+		statements = append(statements, stmt)
+	})
+	return statements
+}
+
+func (d *jsonDecoder) expressions(key string) []Expression {
+	var expressions []Expression
+	d.list(key, func(isNil bool) {
+		if !isNil {
+			expressions = []Expression{}
+		}
+	}, func(data json.RawMessage) {
+		node := d.decode(data)
+		expr, ok := node.(Expression)
+		d.expect(key, node, ok, "an expression")
+		expressions = append(expressions, expr)
+	})
+	return expressions
+}
+
+func (d *jsonDecoder) identifiers(key string) []*Identifier {
+	var identifiers []*Identifier
+	d.list(key, func(isNil bool) {
+		if !isNil {
+			identifiers = []*Identifier{}
+		}
+	}, func(data json.RawMessage) {
+		node := d.decode(data)
+		ident, ok := node.(*Identifier)
+		d.expect(key, node, ok, "Identifier")
+		identifiers = append(identifiers, ident)
+	})
+	return identifiers
+}
+
+func isNull(data []byte) bool {
+	return string(bytes.TrimSpace(data)) == "null"
+}
@@ -0,0 +1,149 @@
+package ast_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+)
+
+const jsonInput = `
+import "lib" as lib;
+/// Documented.
+export const n: int = 1;
+class A extends B { init(v) { self.v = v; } }
+enum Shape { Circle(r), Empty }
+try { throw "x"; } catch (e) { e; } finally { 1; }
+let g = fn(xs: [int], h: {string: bool}) -> generator { defer puts(1); yield -1; };
+for (k, v) in xs { k += v; }
+if (true) { 1..=3; } else { [x * 2 for x in xs if x > 0]; }
+{k: v for k in xs};
+let m = macro(a) { quote(unquote(a)); };
+a.b[0] = lib.c;
+xs[1:-1:2];
+fn() {};
+{"b": 2, "a": [1, {true: 3}]};
+`
+
+func TestJSONRoundTrip(t *testing.T) {
+	program := parse(t, jsonInput)
+
+	data, err := json.Marshal(program)
+	if err != nil {
+		t.Fatalf("json.Marshal returned error: %s", err)
+	}
+
+	decoded := &ast.Program{}
+	if err := json.Unmarshal(data, decoded); err != nil {
+		t.Fatalf("json.Unmarshal returned error: %s", err)
+	}
+
+	if !reflect.DeepEqual(decoded, program) {
+		t.Errorf("decoded tree differs from the original.\nwant=%s\ngot= %s",
+			program.String(), decoded.String())
+	}
+}
+
+func TestJSONSchema(t *testing.T) {
+	program := parse(t, "x + 1;")
+
+	data, err := ast.MarshalNode(program)
+	if err != nil {
+		t.Fatalf("MarshalNode returned error: %s", err)
+	}
+
+	expected := `{"kind":"Program","version":1,"statements":[` +
+		`{"kind":"ExpressionStatement","token":{"type":"IDENT","literal":"x"},"line":1,"column":1,"expression":` +
+		`{"kind":"InfixExpression","token":{"type":"+","literal":"+"},"line":1,"column":3,` +
+		`"left":{"kind":"Identifier","token":{"type":"IDENT","literal":"x"},"line":1,"column":1,"value":"x"},` +
+		`"operator":"+",` +
+		`"right":{"kind":"IntegerLiteral","token":{"type":"INT","literal":"1"},"line":1,"column":5,"value":1}}}]}`
+
+	if string(data) != expected {
+		t.Errorf("wrong encoding.\nwant=%s\ngot= %s", expected, data)
+	}
+}
+
+func TestJSONNodes(t *testing.T) {
+	program := parse(t, "let f = fn(x: int) { x };")
+	let := program.Statements[0].(*ast.LetStatement)
+
+	data, err := ast.MarshalNode(let.Value)
+	if err != nil {
+		t.Fatalf("MarshalNode returned error: %s", err)
+	}
+
+	decoded, err := ast.UnmarshalNode(data)
+	if err != nil {
+		t.Fatalf("UnmarshalNode returned error: %s", err)
+	}
+
+	if !reflect.DeepEqual(decoded, let.Value) {
+		t.Errorf("decoded function differs. want=%s, got=%s", let.Value, decoded)
+	}
+}
+
+func TestJSONErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`{"kind":"Program","version":99,"statements":[]}`, "Program: unsupported version 99, want 1"},
+		{`{"kind":"Nope"}`, `Nope: unknown node kind "Nope"`},
+		{`{"kind":"Identifier","token":{"type":"IDENT","literal":"x"},"line":1,"column":1}`, `Identifier: missing field "value"`},
+		{`{"kind":"Identifier","value":"x"}`, `Identifier: missing field "token"`},
+		{
+			`{"kind":"LetStatement","token":{"type":"LET","literal":"let"},"line":1,"column":1,` +
+				`"name":{"kind":"IntegerLiteral","token":{"type":"INT","literal":"1"},"line":1,"column":5,"value":1},` +
+				`"type":null,"value":null,"doc":""}`,
+			`LetStatement: field "name": expected Identifier, got IntegerLiteral`,
+		},
+		{
+			`{"kind":"Program","version":1,"statements":[` +
+				`{"kind":"Identifier","token":{"type":"IDENT","literal":"x"},"line":1,"column":1,"value":"x"}]}`,
+			`Program: field "statements": expected a statement, got Identifier`,
+		},
+		{
+			`{"kind":"ExpressionStatement","token":{"type":"IDENT","literal":"x"},"line":1,"column":1,` +
+				`"expression":{"kind":"BlockStatement","token":{"type":"{","literal":"{"},"line":1,"column":1,"statements":[]}}`,
+			`ExpressionStatement: field "expression": expected an expression, got BlockStatement`,
+		},
+		{
+			`{"kind":"MacroLiteral","token":{"type":"MACRO","literal":"macro"},"line":1,"column":1,` +
+				`"parameters":[{"kind":"Boolean","token":{"type":"TRUE","literal":"true"},"line":1,"column":7,"value":true}],` +
+				`"body":null}`,
+			`MacroLiteral: field "parameters": expected Identifier, got Boolean`,
+		},
+		{
+			`{"kind":"TypeAnnotation","token":{"type":"IDENT","literal":"int"},"line":1,"column":1,"name":"int",` +
+				`"key":null,"elem":{"kind":"EnumVariant","name":null,"fields":null}}`,
+			`TypeAnnotation: field "elem": expected TypeAnnotation, got EnumVariant`,
+		},
+	}
+
+	for _, tt := range tests {
+		_, err := ast.UnmarshalNode([]byte(tt.input))
+		if err == nil {
+			t.Errorf("input %s: expected error %q, got none", tt.input, tt.expected)
+			continue
+		}
+		if !strings.Contains(err.Error(), tt.expected) {
+			t.Errorf("input %s: expected error %q, got %q", tt.input, tt.expected, err)
+		}
+	}
+
+	// Absent optional children are null, not of the wrong kind.
+	let := `{"kind":"LetStatement","token":{"type":"LET","literal":"let"},"line":1,"column":1,` +
+		`"name":null,"type":null,"value":null,"doc":""}`
+	if _, err := ast.UnmarshalNode([]byte(let)); err != nil {
+		t.Errorf("unexpected error for null children: %s", err)
+	}
+
+	var program ast.Program
+	err := json.Unmarshal([]byte(`{"kind":"Identifier","token":{"type":"IDENT","literal":"x"},"line":1,"column":1,"value":"x"}`), &program)
+	if err == nil || err.Error() != "expected a Program, got *ast.Identifier" {
+		t.Errorf("wrong error for a non-program. got=%v", err)
+	}
+}
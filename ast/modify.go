@@ -0,0 +1,327 @@
+package ast
+
+// ModifierFunc is called for every node visited by Modify. The node it
+// returns replaces the visited one.
+type ModifierFunc func(Node) Node
+
+// Modify rebuilds the tree bottom-up, replacing each node with the result
+// of calling modifier on it. Children are modified before their parent is
+// handed to modifier. Nodes are copied rather than changed in place, so the
+// original tree can be modified again, as happens on every macro call.
+// Copies keep the tokens, and so the positions, of the original nodes.
+// Optional children that are absent stay nil and are not handed to
+// modifier. A replacement that does not fit where its node was, such as an
+// expression returned for a block, leaves that child nil.
+func Modify(node Node, modifier ModifierFunc) Node {
+	switch node := node.(type) {
+	case *Program:
+		n := *node
+		n.Statements = modifyStatements(node.Statements, modifier)
+		return modifier(&n)
+
+	case *ExpressionStatement:
+		n := *node
+		n.Expression, _ = Modify(node.Expression, modifier).(Expression)
+		return modifier(&n)
+
+	case *BlockStatement:
+		n := *node
+		n.Statements = modifyStatements(node.Statements, modifier)
+		return modifier(&n)
+
+	case *ReturnStatement:
+		n := *node
+		n.ReturnValue, _ = Modify(node.ReturnValue, modifier).(Expression)
+		return modifier(&n)
+
+	case *LetStatement:
+		n := *node
+		n.Name = modifyIdentifier(node.Name, modifier)
+		n.Type = modifyType(node.Type, modifier)
+		n.Value, _ = Modify(node.Value, modifier).(Expression)
+		return modifier(&n)
+
+	case *ClassStatement:
+		n := *node
+		n.Name = modifyIdentifier(node.Name, modifier)
+		n.Superclass = modifyIdentifier(node.Superclass, modifier)
+		if node.Methods != nil {
+			n.Methods = make([]*FunctionLiteral, len(node.Methods))
+			for i, method := range node.Methods {
+				n.Methods[i], _ = Modify(method, modifier).(*FunctionLiteral)
+			}
+		}
+		return modifier(&n)
+
+	case *EnumStatement:
+		n := *node
+		n.Name = modifyIdentifier(node.Name, modifier)
+		if node.Variants != nil {
+			n.Variants = make([]*EnumVariant, len(node.Variants))
+			for i, variant := range node.Variants {
+				n.Variants[i], _ = Modify(variant, modifier).(*EnumVariant)
+			}
+		}
+		return modifier(&n)
+
+	case *EnumVariant:
+		n := *node
+		n.Name = modifyIdentifier(node.Name, modifier)
+		n.Fields = modifyIdentifiers(node.Fields, modifier)
+		return modifier(&n)
+
+	case *TryStatement:
+		n := *node
+		n.Block = modifyBlock(node.Block, modifier)
+		n.CatchParam = modifyIdentifier(node.CatchParam, modifier)
+		n.Catch = modifyBlock(node.Catch, modifier)
+		n.Finally = modifyBlock(node.Finally, modifier)
+		return modifier(&n)
+
+	case *ThrowStatement:
+		n := *node
+		n.Value, _ = Modify(node.Value, modifier).(Expression)
+		return modifier(&n)
+
+	case *DeferStatement:
+		n := *node
+		n.Expression, _ = Modify(node.Expression, modifier).(Expression)
+		return modifier(&n)
+
+	case *ImportStatement:
+		n := *node
+		if node.Path != nil {
+			n.Path, _ = Modify(node.Path, modifier).(*StringLiteral)
+		}
+		n.Alias = modifyIdentifier(node.Alias, modifier)
+		return modifier(&n)
+
+	case *ExportStatement:
+		n := *node
+		n.Statement, _ = Modify(node.Statement, modifier).(Statement)
+		return modifier(&n)
+
+	case *YieldStatement:
+		n := *node
+		n.Value, _ = Modify(node.Value, modifier).(Expression)
+		return modifier(&n)
+
+	case *ForStatement:
+		n := *node
+		n.Key = modifyIdentifier(node.Key, modifier)
+		n.Value = modifyIdentifier(node.Value, modifier)
+		n.Iterable, _ = Modify(node.Iterable, modifier).(Expression)
+		n.Body = modifyBlock(node.Body, modifier)
+		return modifier(&n)
+
+	case *InfixExpression:
+		n := *node
+		n.Left, _ = Modify(node.Left, modifier).(Expression)
+		n.Right, _ = Modify(node.Right, modifier).(Expression)
+		return modifier(&n)
+
+	case *PrefixExpression:
+		n := *node
+		n.Right, _ = Modify(node.Right, modifier).(Expression)
+		return modifier(&n)
+
+	case *IndexExpression:
+		n := *node
+		n.Left, _ = Modify(node.Left, modifier).(Expression)
+		n.Index, _ = Modify(node.Index, modifier).(Expression)
+		return modifier(&n)
+
+	case *SliceExpression:
+		n := *node
+		n.Left, _ = Modify(node.Left, modifier).(Expression)
+		n.Start, _ = Modify(node.Start, modifier).(Expression)
+		n.End, _ = Modify(node.End, modifier).(Expression)
+		n.Step, _ = Modify(node.Step, modifier).(Expression)
+		return modifier(&n)
+
+	case *IfExpression:
+		n := *node
+		n.Condition, _ = Modify(node.Condition, modifier).(Expression)
+		n.Consequence = modifyBlock(node.Consequence, modifier)
+		n.Alternative = modifyBlock(node.Alternative, modifier)
+		return modifier(&n)
+
+	case *FunctionLiteral:
+		n := *node
+		n.Parameters = modifyIdentifiers(node.Parameters, modifier)
+		if node.ParameterTypes != nil {
+			n.ParameterTypes = make([]*TypeAnnotation, len(node.ParameterTypes))
+			for i, typ := range node.ParameterTypes {
+				n.ParameterTypes[i] = modifyType(typ, modifier)
+			}
+		}
+		n.ReturnType = modifyType(node.ReturnType, modifier)
+		n.Body = modifyBlock(node.Body, modifier)
+		return modifier(&n)
+
+	case *MacroLiteral:
+		n := *node
+		n.Parameters = modifyIdentifiers(node.Parameters, modifier)
+		n.Body = modifyBlock(node.Body, modifier)
+		return modifier(&n)
+
+	case *CallExpression:
+		n := *node
+		n.Function, _ = Modify(node.Function, modifier).(Expression)
+		n.Arguments = modifyExpressions(node.Arguments, modifier)
+		return modifier(&n)
+
+	case *ArrayLiteral:
+		n := *node
+		n.Elements = modifyExpressions(node.Elements, modifier)
+		return modifier(&n)
+
+	case *HashLiteral:
+		n := *node
+		if node.Pairs != nil {
+			n.Pairs = make([]HashPair, len(node.Pairs))
+		}
+		for i, pair := range node.Pairs {
+			n.Pairs[i].Key, _ = Modify(pair.Key, modifier).(Expression)
+			n.Pairs[i].Value, _ = Modify(pair.Value, modifier).(Expression)
+		}
+		return modifier(&n)
+
+	case *MemberExpression:
+		n := *node
+		n.Object, _ = Modify(node.Object, modifier).(Expression)
+		n.Property = modifyIdentifier(node.Property, modifier)
+		return modifier(&n)
+
+	case *AssignExpression:
+		n := *node
+		n.Target, _ = Modify(node.Target, modifier).(Expression)
+		n.Value, _ = Modify(node.Value, modifier).(Expression)
+		return modifier(&n)
+
+	case *RangeExpression:
+		n := *node
+		n.Start, _ = Modify(node.Start, modifier).(Expression)
+		n.End, _ = Modify(node.End, modifier).(Expression)
+		return modifier(&n)
+
+	case *ComprehensionClause:
+		n := *node
+		n.Key = modifyIdentifier(node.Key, modifier)
+		n.Value = modifyIdentifier(node.Value, modifier)
+		n.Iterable, _ = Modify(node.Iterable, modifier).(Expression)
+		n.Condition, _ = Modify(node.Condition, modifier).(Expression)
+		return modifier(&n)
+
+	case *ArrayComprehension:
+		n := *node
+		n.Element, _ = Modify(node.Element, modifier).(Expression)
+		if node.Clause != nil {
+			n.Clause, _ = Modify(node.Clause, modifier).(*ComprehensionClause)
+		}
+		return modifier(&n)
+
+	case *HashComprehension:
+		n := *node
+		n.Key, _ = Modify(node.Key, modifier).(Expression)
+		n.Value, _ = Modify(node.Value, modifier).(Expression)
+		if node.Clause != nil {
+			n.Clause, _ = Modify(node.Clause, modifier).(*ComprehensionClause)
+		}
+		return modifier(&n)
+
+	case *TypeAnnotation:
+		n := *node
+		n.Key = modifyType(node.Key, modifier)
+		n.Elem = modifyType(node.Elem, modifier)
+		return modifier(&n)
+
+	case *Identifier:
+		n := *node
+		return modifier(&n)
+
+	case *IntegerLiteral:
+		n := *node
+		return modifier(&n)
+
+	case *StringLiteral:
+		n := *node
+		return modifier(&n)
+
+	case *Boolean:
+		n := *node
+		return modifier(&n)
+
+	case nil:
+		return nil
+
+	default:
+		return modifier(node)
+	}
+}
+
+func modifyStatements(statements []Statement, modifier ModifierFunc) []Statement {
+	if statements == nil {
+		return nil
+	}
+
+	modified := make([]Statement, len(statements))
+	for i, statement := range statements {
+		modified[i], _ = Modify(statement, modifier).(Statement)
+	}
+	return modified
+}
+
+func modifyExpressions(expressions []Expression, modifier ModifierFunc) []Expression {
+	if expressions == nil {
+		return nil
+	}
+
+	modified := make([]Expression, len(expressions))
+	for i, expression := range expressions {
+		modified[i], _ = Modify(expression, modifier).(Expression)
+	}
+	return modified
+}
+
+func modifyIdentifiers(identifiers []*Identifier, modifier ModifierFunc) []*Identifier {
+	if identifiers == nil {
+		return nil
+	}
+
+	modified := make([]*Identifier, len(identifiers))
+	for i, identifier := range identifiers {
+		modified[i] = modifyIdentifier(identifier, modifier)
+	}
+	return modified
+}
+
+// The helpers below skip absent optional children, which would otherwise
+// reach modifier as typed nil pointers.
+
+func modifyIdentifier(identifier *Identifier, modifier ModifierFunc) *Identifier {
+	if identifier == nil {
+		return nil
+	}
+
+	modified, _ := Modify(identifier, modifier).(*Identifier)
+	return modified
+}
+
+func modifyBlock(block *BlockStatement, modifier ModifierFunc) *BlockStatement {
+	if block == nil {
+		return nil
+	}
+
+	modified, _ := Modify(block, modifier).(*BlockStatement)
+	return modified
+}
+
+func modifyType(typ *TypeAnnotation, modifier ModifierFunc) *TypeAnnotation {
+	if typ == nil {
+		return nil
+	}
+
+	modified, _ := Modify(typ, modifier).(*TypeAnnotation)
+	return modified
+}
@@ -0,0 +1,356 @@
+package ast
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Devashish08/InterPreter-Compiler/token"
+)
+
+func TestModify(t *testing.T) {
+	one := func() Expression { return &IntegerLiteral{Value: 1} }
+	two := func() Expression { return &IntegerLiteral{Value: 2} }
+
+	turnOneIntoTwo := func(node Node) Node {
+		integer, ok := node.(*IntegerLiteral)
+		if !ok {
+			return node
+		}
+
+		if integer.Value != 1 {
+			return node
+		}
+
+		integer.Value = 2
+		return integer
+	}
+
+	tests := []struct {
+		input    Node
+		expected Node
+	}{
+		{
+			one(),
+			two(),
+		},
+		{
+			&Program{
+				Statements: []Statement{
+					&ExpressionStatement{Expression: one()},
+				},
+			},
+			&Program{
+				Statements: []Statement{
+					&ExpressionStatement{Expression: two()},
+				},
+			},
+		},
+		{
+			&InfixExpression{Left: one(), Operator: "+", Right: two()},
+			&InfixExpression{Left: two(), Operator: "+", Right: two()},
+		},
+		{
+			&InfixExpression{Left: two(), Operator: "+", Right: one()},
+			&InfixExpression{Left: two(), Operator: "+", Right: two()},
+		},
+		{
+			&PrefixExpression{Operator: "-", Right: one()},
+			&PrefixExpression{Operator: "-", Right: two()},
+		},
+		{
+			&IndexExpression{Left: one(), Index: one()},
+			&IndexExpression{Left: two(), Index: two()},
+		},
+		{
+			&IfExpression{
+				Condition: one(),
+				Consequence: &BlockStatement{
+					Statements: []Statement{
+						&ExpressionStatement{Expression: one()},
+					},
+				},
+				Alternative: &BlockStatement{
+					Statements: []Statement{
+						&ExpressionStatement{Expression: one()},
+					},
+				},
+			},
+			&IfExpression{
+				Condition: two(),
+				Consequence: &BlockStatement{
+					Statements: []Statement{
+						&ExpressionStatement{Expression: two()},
+					},
+				},
+				Alternative: &BlockStatement{
+					Statements: []Statement{
+						&ExpressionStatement{Expression: two()},
+					},
+				},
+			},
+		},
+		{
+			&ReturnStatement{ReturnValue: one()},
+			&ReturnStatement{ReturnValue: two()},
+		},
+		{
+			&LetStatement{Value: one()},
+			&LetStatement{Value: two()},
+		},
+		{
+			&FunctionLiteral{
+				Parameters: []*Identifier{},
+				Body: &BlockStatement{
+					Statements: []Statement{
+						&ExpressionStatement{Expression: one()},
+					},
+				},
+			},
+			&FunctionLiteral{
+				Parameters: []*Identifier{},
+				Body: &BlockStatement{
+					Statements: []Statement{
+						&ExpressionStatement{Expression: two()},
+					},
+				},
+			},
+		},
+		{
+			&CallExpression{Function: &Identifier{Value: "f"}, Arguments: []Expression{one(), one()}},
+			&CallExpression{Function: &Identifier{Value: "f"}, Arguments: []Expression{two(), two()}},
+		},
+		{
+			&ArrayLiteral{Elements: []Expression{one(), one()}},
+			&ArrayLiteral{Elements: []Expression{two(), two()}},
+		},
+		{
+			&SliceExpression{Left: one(), Start: one(), Step: one()},
+			&SliceExpression{Left: two(), Start: two(), Step: two()},
+		},
+		{
+			&MemberExpression{Object: one(), Property: &Identifier{Value: "x"}},
+			&MemberExpression{Object: two(), Property: &Identifier{Value: "x"}},
+		},
+		{
+			&AssignExpression{Target: &Identifier{Value: "x"}, Operator: "=", Value: one()},
+			&AssignExpression{Target: &Identifier{Value: "x"}, Operator: "=", Value: two()},
+		},
+		{
+			&RangeExpression{Start: one(), End: one()},
+			&RangeExpression{Start: two(), End: two()},
+		},
+		{
+			&ThrowStatement{Value: one()},
+			&ThrowStatement{Value: two()},
+		},
+		{
+			&DeferStatement{Expression: one()},
+			&DeferStatement{Expression: two()},
+		},
+		{
+			&YieldStatement{Value: one()},
+			&YieldStatement{Value: two()},
+		},
+		{
+			&ExportStatement{Statement: &LetStatement{Value: one()}},
+			&ExportStatement{Statement: &LetStatement{Value: two()}},
+		},
+		{
+			&ForStatement{
+				Value:    &Identifier{Value: "x"},
+				Iterable: one(),
+				Body:     &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: one()}}},
+			},
+			&ForStatement{
+				Value:    &Identifier{Value: "x"},
+				Iterable: two(),
+				Body:     &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: two()}}},
+			},
+		},
+		{
+			&TryStatement{
+				Block:   &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: one()}}},
+				Finally: &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: one()}}},
+			},
+			&TryStatement{
+				Block:   &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: two()}}},
+				Finally: &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: two()}}},
+			},
+		},
+		{
+			&ClassStatement{
+				Name: &Identifier{Value: "A"},
+				Methods: []*FunctionLiteral{{
+					Parameters: []*Identifier{},
+					Body:       &BlockStatement{Statements: []Statement{&ReturnStatement{ReturnValue: one()}}},
+				}},
+			},
+			&ClassStatement{
+				Name: &Identifier{Value: "A"},
+				Methods: []*FunctionLiteral{{
+					Parameters: []*Identifier{},
+					Body:       &BlockStatement{Statements: []Statement{&ReturnStatement{ReturnValue: two()}}},
+				}},
+			},
+		},
+		{
+			&ArrayComprehension{
+				Element: one(),
+				Clause:  &ComprehensionClause{Value: &Identifier{Value: "x"}, Iterable: one(), Condition: one()},
+			},
+			&ArrayComprehension{
+				Element: two(),
+				Clause:  &ComprehensionClause{Value: &Identifier{Value: "x"}, Iterable: two(), Condition: two()},
+			},
+		},
+		{
+			&HashComprehension{
+				Key:    one(),
+				Value:  one(),
+				Clause: &ComprehensionClause{Value: &Identifier{Value: "x"}, Iterable: one()},
+			},
+			&HashComprehension{
+				Key:    two(),
+				Value:  two(),
+				Clause: &ComprehensionClause{Value: &Identifier{Value: "x"}, Iterable: two()},
+			},
+		},
+		{
+			&MacroLiteral{
+				Parameters: []*Identifier{},
+				Body:       &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: one()}}},
+			},
+			&MacroLiteral{
+				Parameters: []*Identifier{},
+				Body:       &BlockStatement{Statements: []Statement{&ExpressionStatement{Expression: two()}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		modified := Modify(tt.input, turnOneIntoTwo)
+
+		equal := reflect.DeepEqual(modified, tt.expected)
+		if !equal {
+			t.Errorf("not equal. got=%#v, want=%#v", modified, tt.expected)
+		}
+	}
+
+	hashLiteral := &HashLiteral{
+		Pairs: []HashPair{
+			{Key: one(), Value: one()},
+			{Key: one(), Value: one()},
+		},
+	}
+
+	modified, _ := Modify(hashLiteral, turnOneIntoTwo).(*HashLiteral)
+
+	for _, pair := range modified.Pairs {
+		key, _ := pair.Key.(*IntegerLiteral)
+		if key.Value != 2 {
+			t.Errorf("value is not %d, got=%d", 2, key.Value)
+		}
+		val, _ := pair.Value.(*IntegerLiteral)
+		if val.Value != 2 {
+			t.Errorf("value is not %d, got=%d", 2, val.Value)
+		}
+	}
+}
+
+func TestModifyKeepsOriginalTree(t *testing.T) {
+	original := &Program{
+		Statements: []Statement{
+			&ExpressionStatement{
+				Expression: &InfixExpression{
+					Left:     &Identifier{Value: "x"},
+					Operator: "+",
+					Right:    &Identifier{Value: "y"},
+				},
+			},
+		},
+	}
+
+	renameX := func(node Node) Node {
+		ident, ok := node.(*Identifier)
+		if !ok || ident.Value != "x" {
+			return node
+		}
+		return &Identifier{Value: "z"}
+	}
+
+	modified := Modify(original, renameX)
+
+	if modified.String() != "(z + y)" {
+		t.Errorf("modified.String() wrong. got=%q", modified.String())
+	}
+
+	if original.String() != "(x + y)" {
+		t.Errorf("original tree was changed. got=%q", original.String())
+	}
+}
+
+func TestModifyReachesIdentifiersAndTypes(t *testing.T) {
+	rename := func(node Node) Node {
+		switch node := node.(type) {
+		case *Identifier:
+			if node.Value == "x" {
+				node.Value = "y"
+			}
+		case *TypeAnnotation:
+			if node.Name == "int" {
+				node.Name = "string"
+			}
+		}
+		return node
+	}
+
+	input := &FunctionLiteral{
+		Token:          token.Token{Type: token.FUNCTION, Literal: "fn"},
+		Parameters:     []*Identifier{{Value: "x"}, {Value: "z"}},
+		ParameterTypes: []*TypeAnnotation{{Elem: &TypeAnnotation{Name: "int"}}, nil},
+		ReturnType:     &TypeAnnotation{Name: "int"},
+		Body: &BlockStatement{Statements: []Statement{
+			&ForStatement{
+				Token:    token.Token{Type: token.FOR, Literal: "for"},
+				Value:    &Identifier{Value: "x"},
+				Iterable: &Identifier{Value: "x"},
+				Body:     &BlockStatement{},
+			},
+		}},
+	}
+
+	modified := Modify(input, rename)
+
+	expected := "fn(y: [string], z) -> string for y in y "
+	if modified.String() != expected {
+		t.Errorf("modified.String() wrong. want=%q, got=%q", expected, modified.String())
+	}
+	if input.String() != "fn(x: [int], z) -> int for x in x " {
+		t.Errorf("original tree was changed. got=%q", input.String())
+	}
+}
+
+func TestModifyKeepsPositions(t *testing.T) {
+	tok := token.Token{Type: token.PLUS, Literal: "+", Line: 3, Column: 7}
+	input := &InfixExpression{
+		Token:    tok,
+		Left:     &IntegerLiteral{Token: token.Token{Type: token.INT, Literal: "1", Line: 3, Column: 5}, Value: 1},
+		Operator: "+",
+		Right:    &Identifier{Token: token.Token{Type: token.IDENT, Literal: "x", Line: 3, Column: 9}, Value: "x"},
+	}
+
+	modified := Modify(input, func(node Node) Node { return node }).(*InfixExpression)
+
+	if modified.Token != tok {
+		t.Errorf("infix token changed. got=%+v", modified.Token)
+	}
+	if modified.Left.(*IntegerLiteral).Token.Column != 5 {
+		t.Errorf("left position changed. got=%+v", modified.Left.(*IntegerLiteral).Token)
+	}
+	if modified.Right.(*Identifier).Token.Column != 9 {
+		t.Errorf("right position changed. got=%+v", modified.Right.(*Identifier).Token)
+	}
+	if modified == input {
+		t.Errorf("Modify returned the original node instead of a copy")
+	}
+}
@@ -0,0 +1,235 @@
+package ast
+
+// A Visitor's Visit method is called by Walk for each node. If the visitor
+// it returns is not nil, Walk visits each child of the node with it and
+// then calls its Visit method with nil.
+type Visitor interface {
+	Visit(node Node) (w Visitor)
+}
+
+// Walk traverses the tree rooted at node depth-first, in source order.
+// It starts by calling v.Visit(node); node must not be nil. Optional
+// children that are absent, such as the alternative of an if expression
+// without else, are skipped.
+func Walk(v Visitor, node Node) {
+	if v = v.Visit(node); v == nil {
+		return
+	}
+
+	switch n := node.(type) {
+	case *Program:
+		walkStatements(v, n.Statements)
+
+	case *LetStatement:
+		Walk(v, n.Name)
+		if n.Type != nil {
+			Walk(v, n.Type)
+		}
+		walkExpression(v, n.Value)
+
+	case *ReturnStatement:
+		walkExpression(v, n.ReturnValue)
+
+	case *ExpressionStatement:
+		walkExpression(v, n.Expression)
+
+	case *BlockStatement:
+		walkStatements(v, n.Statements)
+
+	case *ClassStatement:
+		Walk(v, n.Name)
+		if n.Superclass != nil {
+			Walk(v, n.Superclass)
+		}
+		for _, method := range n.Methods {
+			Walk(v, method)
+		}
+
+	case *EnumStatement:
+		Walk(v, n.Name)
+		for _, variant := range n.Variants {
+			Walk(v, variant)
+		}
+
+	case *EnumVariant:
+		Walk(v, n.Name)
+		walkIdentifiers(v, n.Fields)
+
+	case *TryStatement:
+		Walk(v, n.Block)
+		if n.CatchParam != nil {
+			Walk(v, n.CatchParam)
+		}
+		if n.Catch != nil {
+			Walk(v, n.Catch)
+		}
+		if n.Finally != nil {
+			Walk(v, n.Finally)
+		}
+
+	case *ThrowStatement:
+		walkExpression(v, n.Value)
+
+	case *DeferStatement:
+		walkExpression(v, n.Expression)
+
+	case *ImportStatement:
+		Walk(v, n.Path)
+		Walk(v, n.Alias)
+
+	case *ExportStatement:
+		if n.Statement != nil {
+			Walk(v, n.Statement)
+		}
+
+	case *YieldStatement:
+		walkExpression(v, n.Value)
+
+	case *ForStatement:
+		if n.Key != nil {
+			Walk(v, n.Key)
+		}
+		Walk(v, n.Value)
+		walkExpression(v, n.Iterable)
+		Walk(v, n.Body)
+
+	case *PrefixExpression:
+		walkExpression(v, n.Right)
+
+	case *InfixExpression:
+		walkExpression(v, n.Left)
+		walkExpression(v, n.Right)
+
+	case *IfExpression:
+		walkExpression(v, n.Condition)
+		Walk(v, n.Consequence)
+		if n.Alternative != nil {
+			Walk(v, n.Alternative)
+		}
+
+	case *FunctionLiteral:
+		for i, param := range n.Parameters {
+			Walk(v, param)
+			if n.ParameterTypes != nil && n.ParameterTypes[i] != nil {
+				Walk(v, n.ParameterTypes[i])
+			}
+		}
+		if n.ReturnType != nil {
+			Walk(v, n.ReturnType)
+		}
+		Walk(v, n.Body)
+
+	case *MacroLiteral:
+		walkIdentifiers(v, n.Parameters)
+		Walk(v, n.Body)
+
+	case *CallExpression:
+		walkExpression(v, n.Function)
+		walkExpressions(v, n.Arguments)
+
+	case *ArrayLiteral:
+		walkExpressions(v, n.Elements)
+
+	case *IndexExpression:
+		walkExpression(v, n.Left)
+		walkExpression(v, n.Index)
+
+	case *SliceExpression:
+		walkExpression(v, n.Left)
+		walkExpression(v, n.Start)
+		walkExpression(v, n.End)
+		walkExpression(v, n.Step)
+
+	case *HashLiteral:
+		for _, pair := range n.Pairs {
+			walkExpression(v, pair.Key)
+			walkExpression(v, pair.Value)
+		}
+
+	case *MemberExpression:
+		walkExpression(v, n.Object)
+		Walk(v, n.Property)
+
+	case *AssignExpression:
+		walkExpression(v, n.Target)
+		walkExpression(v, n.Value)
+
+	case *RangeExpression:
+		walkExpression(v, n.Start)
+		walkExpression(v, n.End)
+
+	case *ComprehensionClause:
+		if n.Key != nil {
+			Walk(v, n.Key)
+		}
+		Walk(v, n.Value)
+		walkExpression(v, n.Iterable)
+		walkExpression(v, n.Condition)
+
+	case *ArrayComprehension:
+		walkExpression(v, n.Element)
+		Walk(v, n.Clause)
+
+	case *HashComprehension:
+		walkExpression(v, n.Key)
+		walkExpression(v, n.Value)
+		Walk(v, n.Clause)
+
+	case *TypeAnnotation:
+		if n.Key != nil {
+			Walk(v, n.Key)
+		}
+		if n.Elem != nil {
+			Walk(v, n.Elem)
+		}
+
+	case *Identifier, *Boolean, *IntegerLiteral, *StringLiteral:
+		// leaves
+	}
+
+	v.Visit(nil)
+}
+
+type inspector func(Node) bool
+
+func (f inspector) Visit(node Node) Visitor {
+	if f(node) {
+		return f
+	}
+	return nil
+}
+
+// Inspect traverses the tree rooted at node like Walk, calling f for each
+// node. If f returns true, Inspect visits the children of the node and then
+// calls f(nil).
+func Inspect(node Node, f func(Node) bool) {
+	Walk(inspector(f), node)
+}
+
+func walkStatements(v Visitor, statements []Statement) {
+	for _, stmt := range statements {
+		if stmt != nil {
+			Walk(v, stmt)
+		}
+	}
+}
+
+func walkExpressions(v Visitor, expressions []Expression) {
+	for _, expr := range expressions {
+		walkExpression(v, expr)
+	}
+}
+
+// walkExpression walks expr unless it is nil, which is how optional
+// expressions such as a missing slice bound are represented.
+func walkExpression(v Visitor, expr Expression) {
+	if expr != nil {
+		Walk(v, expr)
+	}
+}
+
+func walkIdentifiers(v Visitor, identifiers []*Identifier) {
+	for _, ident := range identifiers {
+		Walk(v, ident)
+	}
+}
@@ -0,0 +1,163 @@
+package ast_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/lexer"
+	"github.com/Devashish08/InterPreter-Compiler/parser"
+)
+
+func parse(t *testing.T, input string) *ast.Program {
+	t.Helper()
+
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+	return program
+}
+
+// collect lists the nodes Inspect visits, as "Type" or "Type:text" for
+// leaves.
+func collect(node ast.Node) []string {
+	visited := []string{}
+	ast.Inspect(node, func(n ast.Node) bool {
+		if n == nil {
+			return false
+		}
+
+		name := reflect.TypeOf(n).Elem().Name()
+		switch n := n.(type) {
+		case *ast.Identifier, *ast.IntegerLiteral, *ast.StringLiteral, *ast.TypeAnnotation:
+			name += ":" + n.String()
+		}
+		visited = append(visited, name)
+		return true
+	})
+	return visited
+}
+
+func TestInspectOrder(t *testing.T) {
+	input := `let f: fn = fn(x: int, y) -> [int] { return [x, y[1:]]; };
+{"b": 2, "a": 1};`
+
+	expected := []string{
+		"Program",
+		"LetStatement", "Identifier:f", "TypeAnnotation:fn",
+		"FunctionLiteral",
+		"Identifier:x", "TypeAnnotation:int", "Identifier:y",
+		"TypeAnnotation:[int]", "TypeAnnotation:int",
+		"BlockStatement", "ReturnStatement", "ArrayLiteral",
+		"Identifier:x",
+		"SliceExpression", "Identifier:y", "IntegerLiteral:1",
+		"ExpressionStatement", "HashLiteral",
+		"StringLiteral:b", "IntegerLiteral:2",
+		"StringLiteral:a", "IntegerLiteral:1",
+	}
+
+	got := collect(parse(t, input))
+	if !reflect.DeepEqual(got, expected) {
+		t.Errorf("wrong visit order.\nwant=%v\ngot= %v", expected, got)
+	}
+}
+
+func TestInspectCoversAllNodes(t *testing.T) {
+	input := `
+import "lib" as lib;
+export const n = 1;
+class A extends B { init(v) { self.v = v; } }
+enum Shape { Circle(r), Empty }
+try { throw "x"; } catch (e) { e; } finally { 1; }
+let g = fn() { defer puts(1); yield -1; };
+for (k, v) in {1: 2} { k += v; }
+if (true) { 1..=3; } else { [x * 2 for x in xs if x > 0]; }
+{k: v for k in xs};
+let m = macro(a) { quote(unquote(a)); };
+a.b[0] = lib.c;
+`
+
+	expected := []string{
+		"Program", "ImportStatement", "ExportStatement", "LetStatement",
+		"ClassStatement", "EnumStatement", "EnumVariant", "TryStatement",
+		"ThrowStatement", "DeferStatement", "YieldStatement", "ForStatement",
+		"IfExpression", "RangeExpression", "ArrayComprehension",
+		"ComprehensionClause", "HashComprehension", "MacroLiteral",
+		"CallExpression", "AssignExpression", "IndexExpression",
+		"MemberExpression", "PrefixExpression", "InfixExpression",
+		"FunctionLiteral", "BlockStatement", "ExpressionStatement",
+		"HashLiteral", "Identifier", "IntegerLiteral", "StringLiteral",
+		"Boolean",
+	}
+
+	seen := map[string]bool{}
+	ast.Inspect(parse(t, input), func(n ast.Node) bool {
+		if n != nil {
+			seen[reflect.TypeOf(n).Elem().Name()] = true
+		}
+		return true
+	})
+
+	for _, name := range expected {
+		if !seen[name] {
+			t.Errorf("Inspect did not visit any %s", name)
+		}
+	}
+}
+
+func TestInspectPrunes(t *testing.T) {
+	program := parse(t, "let f = fn(x) { x + 1 }; f(2);")
+
+	visited := []string{}
+	ast.Inspect(program, func(n ast.Node) bool {
+		if n == nil {
+			return false
+		}
+		if _, ok := n.(*ast.FunctionLiteral); ok {
+			return false
+		}
+		if ident, ok := n.(*ast.Identifier); ok {
+			visited = append(visited, ident.Value)
+		}
+		return true
+	})
+
+	if fmt.Sprint(visited) != "[f f]" {
+		t.Errorf("expected the function body to be skipped. got=%v", visited)
+	}
+}
+
+type depthVisitor struct {
+	depth    *int
+	maxDepth *int
+}
+
+func (v depthVisitor) Visit(node ast.Node) ast.Visitor {
+	if node == nil {
+		*v.depth--
+		return nil
+	}
+
+	*v.depth++
+	if *v.depth > *v.maxDepth {
+		*v.maxDepth = *v.depth
+	}
+	return v
+}
+
+func TestWalkCallsVisitNilAfterChildren(t *testing.T) {
+	depth, maxDepth := 0, 0
+	ast.Walk(depthVisitor{&depth, &maxDepth}, parse(t, "-(1 + 2);"))
+
+	if depth != 0 {
+		t.Errorf("Visit(nil) calls do not balance visits. depth=%d", depth)
+	}
+	// Program, ExpressionStatement, PrefixExpression, InfixExpression,
+	// IntegerLiteral
+	if maxDepth != 5 {
+		t.Errorf("wrong maximum depth. want=5, got=%d", maxDepth)
+	}
+}
@@ -0,0 +1,166 @@
+/*
+Package doc generates API documentation for a program from the /// doc
+comments that the parser attaches to its let statements.
+
+  - Functions: Lists the functions defined at the top level of a program
+  - Markdown/HTML: Render those functions as a Markdown or HTML page
+*/
+package doc
+
+import (
+	"fmt"
+	"html"
+	"io"
+	"strings"
+
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+)
+
+// Parameter is a function parameter and its type annotation, if any.
+type Parameter struct {
+	Name string
+	Type string // empty when not annotated
+}
+
+// Function documents a function bound by a top-level let statement.
+type Function struct {
+	Name       string
+	Parameters []Parameter
+	ReturnType string // empty when not annotated
+	Doc        string
+}
+
+// Signature renders the function as it is called, such as
+// "add(a: int, b: int) -> int".
+func (f Function) Signature() string {
+	params := []string{}
+	for _, p := range f.Parameters {
+		if p.Type != "" {
+			params = append(params, p.Name+": "+p.Type)
+		} else {
+			params = append(params, p.Name)
+		}
+	}
+
+	signature := f.Name + "(" + strings.Join(params, ", ") + ")"
+	if f.ReturnType != "" {
+		signature += " -> " + f.ReturnType
+	}
+	return signature
+}
+
+// Functions lists, in source order, the functions that program binds with
+// top-level let, const or exported let statements.
+func Functions(program *ast.Program) []Function {
+	functions := []Function{}
+
+	for _, stmt := range program.Statements {
+		if export, ok := stmt.(*ast.ExportStatement); ok {
+			stmt = export.Statement
+		}
+
+		let, ok := stmt.(*ast.LetStatement)
+		if !ok {
+			continue
+		}
+
+		lit, ok := let.Value.(*ast.FunctionLiteral)
+		if !ok {
+			continue
+		}
+
+		fn := Function{Name: let.Name.Value, Doc: let.Doc}
+		for i, param := range lit.Parameters {
+			p := Parameter{Name: param.Value}
+			if lit.ParameterTypes != nil && lit.ParameterTypes[i] != nil {
+				p.Type = lit.ParameterTypes[i].String()
+			}
+			fn.Parameters = append(fn.Parameters, p)
+		}
+		if lit.ReturnType != nil {
+			fn.ReturnType = lit.ReturnType.String()
+		}
+
+		functions = append(functions, fn)
+	}
+
+	return functions
+}
+
+// Markdown writes the documentation of functions as a Markdown page.
+func Markdown(w io.Writer, title string, functions []Function) error {
+	var out strings.Builder
+
+	fmt.Fprintf(&out, "# %s\n", title)
+
+	for _, fn := range functions {
+		fmt.Fprintf(&out, "\n## %s\n\n", fn.Name)
+		fmt.Fprintf(&out, "```\n%s\n```\n", fn.Signature())
+
+		if fn.Doc != "" {
+			fmt.Fprintf(&out, "\n%s\n", fn.Doc)
+		}
+
+		if len(fn.Parameters) > 0 {
+			out.WriteString("\nParameters:\n\n")
+			for _, p := range fn.Parameters {
+				if p.Type != "" {
+					fmt.Fprintf(&out, "- `%s`: %s\n", p.Name, p.Type)
+				} else {
+					fmt.Fprintf(&out, "- `%s`\n", p.Name)
+				}
+			}
+		}
+
+		if fn.ReturnType != "" {
+			fmt.Fprintf(&out, "\nReturns: %s\n", fn.ReturnType)
+		}
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// HTML writes the documentation of functions as a standalone HTML page.
+// Blank lines in doc comments separate paragraphs.
+func HTML(w io.Writer, title string, functions []Function) error {
+	var out strings.Builder
+	esc := html.EscapeString
+
+	out.WriteString("<!DOCTYPE html>\n<html>\n<head>\n")
+	fmt.Fprintf(&out, "<meta charset=\"utf-8\">\n<title>%s</title>\n", esc(title))
+	out.WriteString("</head>\n<body>\n")
+	fmt.Fprintf(&out, "<h1>%s</h1>\n", esc(title))
+
+	for _, fn := range functions {
+		fmt.Fprintf(&out, "<h2 id=\"%s\">%s</h2>\n", esc(fn.Name), esc(fn.Name))
+		fmt.Fprintf(&out, "<pre><code>%s</code></pre>\n", esc(fn.Signature()))
+
+		for _, paragraph := range strings.Split(fn.Doc, "\n\n") {
+			if strings.TrimSpace(paragraph) != "" {
+				fmt.Fprintf(&out, "<p>%s</p>\n", esc(paragraph))
+			}
+		}
+
+		if len(fn.Parameters) > 0 {
+			out.WriteString("<h3>Parameters</h3>\n<ul>\n")
+			for _, p := range fn.Parameters {
+				if p.Type != "" {
+					fmt.Fprintf(&out, "<li><code>%s</code>: %s</li>\n", esc(p.Name), esc(p.Type))
+				} else {
+					fmt.Fprintf(&out, "<li><code>%s</code></li>\n", esc(p.Name))
+				}
+			}
+			out.WriteString("</ul>\n")
+		}
+
+		if fn.ReturnType != "" {
+			fmt.Fprintf(&out, "<p>Returns: %s</p>\n", esc(fn.ReturnType))
+		}
+	}
+
+	out.WriteString("</body>\n</html>\n")
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
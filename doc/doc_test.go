@@ -0,0 +1,93 @@
+package doc
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Devashish08/InterPreter-Compiler/lexer"
+	"github.com/Devashish08/InterPreter-Compiler/parser"
+)
+
+const input = `
+/// Adds two numbers.
+let add = fn(a: int, b: int) -> int { a + b };
+let count = 3;
+/// Says hi to <name>.
+export let greet = fn(name) { "hi " + name };
+`
+
+func TestFunctions(t *testing.T) {
+	functions := parse(t, input)
+
+	if len(functions) != 2 {
+		t.Fatalf("wrong number of functions. want=2, got=%d", len(functions))
+	}
+
+	tests := []struct {
+		signature string
+		doc       string
+	}{
+		{"add(a: int, b: int) -> int", "Adds two numbers."},
+		{"greet(name)", "Says hi to <name>."},
+	}
+
+	for i, tt := range tests {
+		if functions[i].Signature() != tt.signature {
+			t.Errorf("functions[%d]: wrong signature. want=%q, got=%q",
+				i, tt.signature, functions[i].Signature())
+		}
+		if functions[i].Doc != tt.doc {
+			t.Errorf("functions[%d]: wrong doc. want=%q, got=%q", i, tt.doc, functions[i].Doc)
+		}
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	expected := "# lib.monkey\n" +
+		"\n## add\n\n```\nadd(a: int, b: int) -> int\n```\n" +
+		"\nAdds two numbers.\n" +
+		"\nParameters:\n\n- `a`: int\n- `b`: int\n" +
+		"\nReturns: int\n" +
+		"\n## greet\n\n```\ngreet(name)\n```\n" +
+		"\nSays hi to <name>.\n" +
+		"\nParameters:\n\n- `name`\n"
+
+	var out bytes.Buffer
+	if err := Markdown(&out, "lib.monkey", parse(t, input)); err != nil {
+		t.Fatalf("Markdown returned error: %s", err)
+	}
+
+	if out.String() != expected {
+		t.Errorf("wrong markdown. want=\n%s\ngot=\n%s", expected, out.String())
+	}
+}
+
+func TestHTMLEscapes(t *testing.T) {
+	var out bytes.Buffer
+	if err := HTML(&out, "lib.monkey", parse(t, input)); err != nil {
+		t.Fatalf("HTML returned error: %s", err)
+	}
+
+	for _, want := range []string{
+		"<h2 id=\"add\">add</h2>",
+		"<pre><code>add(a: int, b: int) -&gt; int</code></pre>",
+		"<p>Says hi to &lt;name&gt;.</p>",
+		"<li><code>name</code></li>",
+	} {
+		if !bytes.Contains(out.Bytes(), []byte(want)) {
+			t.Errorf("HTML does not contain %q. got=\n%s", want, out.String())
+		}
+	}
+}
+
+func parse(t *testing.T, input string) []Function {
+	t.Helper()
+
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+
+	return Functions(program)
+}
@@ -7,23 +7,30 @@ import (
 	"github.com/Devashish08/InterPreter-Compiler/object"
 )
 
-// Initialize built-in functions
+// GetBuiltins returns the builtin functions by name, with the number of
+// arguments each accepts.
 func GetBuiltins() map[string]*object.Builtin {
 	return map[string]*object.Builtin{
-		"len":    {Fn: builtinLen},
-		"first":  {Fn: builtinFirst},
-		"last":   {Fn: builtinLast},
-		"rest":   {Fn: builtinRest},
-		"push":   {Fn: builtinPush},
-		"puts":   {Fn: builtinPuts},
-		"pop":    {Fn: builtinPop},
-		"sum":    {Fn: builtinSum},
-		"max":    {Fn: builtinMax},
-		"min":    {Fn: builtinMin},
-		"join":   {Fn: builtinJoin},
-		"split":  {Fn: builtinSplit},
-		"upper":  {Fn: builtinUpper},
-		"lower":  {Fn: builtinLower},
+		"len":     {Fn: builtinLen, MinArgs: 1, MaxArgs: 1},
+		"first":   {Fn: builtinFirst, MinArgs: 1, MaxArgs: 1},
+		"last":    {Fn: builtinLast, MinArgs: 1, MaxArgs: 1},
+		"rest":    {Fn: builtinRest, MinArgs: 1, MaxArgs: 1},
+		"push":    {Fn: builtinPush, MinArgs: 2, MaxArgs: 2},
+		"puts":    {Fn: builtinPuts, MinArgs: 0, MaxArgs: -1},
+		"pop":     {Fn: builtinPop, MinArgs: 1, MaxArgs: 1},
+		"sum":     {Fn: builtinSum, MinArgs: 1, MaxArgs: 1},
+		"max":     {Fn: builtinMax, MinArgs: 1, MaxArgs: 1},
+		"min":     {Fn: builtinMin, MinArgs: 1, MaxArgs: 1},
+		"join":    {Fn: builtinJoin, MinArgs: 2, MaxArgs: 2},
+		"split":   {Fn: builtinSplit, MinArgs: 2, MaxArgs: 2},
+		"upper":   {Fn: builtinUpper, MinArgs: 1, MaxArgs: 1},
+		"lower":   {Fn: builtinLower, MinArgs: 1, MaxArgs: 1},
+		"variant": {Fn: builtinVariant, MinArgs: 1, MaxArgs: 1},
+		"error":   {Fn: builtinError, MinArgs: 1, MaxArgs: 2},
+		"freeze":  {Fn: builtinFreeze, MinArgs: 1, MaxArgs: 1},
+		"next":    {Fn: builtinNext, MinArgs: 1, MaxArgs: 1},
+		"close":   {Fn: builtinClose, MinArgs: 1, MaxArgs: 1},
+		"help":    {Fn: builtinHelp, MinArgs: 1, MaxArgs: 1},
 	}
 }
 
@@ -37,6 +44,10 @@ func builtinLen(args ...object.Object) object.Object {
 		return &object.Integer{Value: int64(len(arg.Elements))}
 	case *object.String:
 		return &object.Integer{Value: int64(len(arg.Value))}
+	case *object.Range:
+		return &object.Integer{Value: arg.Len()}
+	case *object.Instance:
+		return instanceLen(arg)
 	default:
 		return NewError("argument to `len` not supported, got %s", args[0].Type())
 	}
@@ -298,3 +309,127 @@ func builtinMin(args ...object.Object) object.Object {
 
 	return &object.Integer{Value: minVal}
 }
+
+func builtinVariant(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return NewError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+
+	value, ok := args[0].(*object.EnumValue)
+	if !ok {
+		return NewError("argument to `variant` must be ENUM_VALUE, got %s", args[0].Type())
+	}
+
+	return &object.String{Value: value.Variant.Name}
+}
+
+func builtinError(args ...object.Object) object.Object {
+	if len(args) != 1 && len(args) != 2 {
+		return NewError("wrong number of arguments. got=%d, want=1 or 2", len(args))
+	}
+
+	kind := UserErrorKind
+	if len(args) == 2 {
+		k, ok := args[0].(*object.String)
+		if !ok {
+			return NewError("first argument to `error` must be STRING, got %s", args[0].Type())
+		}
+		kind = k.Value
+	}
+
+	message, ok := args[len(args)-1].(*object.String)
+	if !ok {
+		return NewError("argument to `error` must be STRING, got %s", args[len(args)-1].Type())
+	}
+
+	return &object.ErrorValue{Error: &object.Error{Kind: kind, Message: message.Value}}
+}
+
+// builtinFreeze makes an array or hash, and every array and hash reachable
+// from it, read-only. It returns its argument so that it can wrap a value,
+// as in `const CONFIG = freeze({...});`.
+func builtinFreeze(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return NewError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+
+	deepFreeze(args[0])
+	return args[0]
+}
+
+func deepFreeze(obj object.Object) {
+	switch obj := obj.(type) {
+	case *object.Array:
+		if obj.Frozen {
+			return
+		}
+		obj.Frozen = true
+		for _, elem := range obj.Elements {
+			deepFreeze(elem)
+		}
+	case *object.Hash:
+		if obj.Frozen {
+			return
+		}
+		obj.Frozen = true
+		for _, pair := range obj.Pairs() {
+			deepFreeze(pair.Key)
+			deepFreeze(pair.Value)
+		}
+	}
+}
+
+// builtinNext resumes a generator and returns the next value it yields, or
+// null once it has finished.
+func builtinNext(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return NewError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+
+	gen, ok := args[0].(*object.Generator)
+	if !ok {
+		return NewError("argument to `next` must be GENERATOR, got %s", args[0].Type())
+	}
+
+	value, ok := gen.Next()
+	if !ok {
+		if value != nil {
+			return value
+		}
+		return NULL
+	}
+
+	return value
+}
+
+func builtinClose(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return NewError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+
+	gen, ok := args[0].(*object.Generator)
+	if !ok {
+		return NewError("argument to `close` must be GENERATOR, got %s", args[0].Type())
+	}
+
+	gen.Close()
+	return NULL
+}
+
+// builtinHelp returns the doc comment of a function defined by a documented
+// let statement, or null when it has none.
+func builtinHelp(args ...object.Object) object.Object {
+	if len(args) != 1 {
+		return NewError("wrong number of arguments. got=%d, want=1", len(args))
+	}
+
+	fn, ok := args[0].(*object.Function)
+	if !ok {
+		return NewError("argument to `help` must be FUNCTION, got %s", args[0].Type())
+	}
+
+	if fn.Doc == "" {
+		return NULL
+	}
+	return &object.String{Value: fn.Doc}
+}
@@ -0,0 +1,126 @@
+package evaluator
+
+import (
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/object"
+)
+
+func evalClassStatement(
+	node *ast.ClassStatement,
+	env *object.Environment,
+) object.Object {
+	class := &object.Class{
+		Name:    node.Name.Value,
+		Methods: make(map[string]*object.Function),
+	}
+
+	if node.Superclass != nil {
+		superclass := evalIdentifier(node.Superclass, env)
+		if IsError(superclass) {
+			return superclass
+		}
+
+		parent, ok := superclass.(*object.Class)
+		if !ok {
+			return NewError("superclass must be a CLASS, got %s", superclass.Type())
+		}
+		class.Superclass = parent
+	}
+
+	for _, method := range node.Methods {
+		class.Methods[method.Name] = &object.Function{
+			Parameters:     method.Parameters,
+			ParameterTypes: method.ParameterTypes,
+			ReturnType:     method.ReturnType,
+			Body:           method.Body,
+			Env:            env,
+			Generator:      method.Generator,
+		}
+	}
+
+	return declare(env, node.Name.Value, class, false)
+}
+
+func instantiateClass(class *object.Class, args []object.Object) object.Object {
+	instance := &object.Instance{Class: class, Fields: make(map[string]object.Object)}
+
+	init, definedIn := class.FindMethod("init")
+	if init == nil {
+		if len(args) != 0 {
+			return NewError("wrong number of arguments. got=%d, want=0", len(args))
+		}
+		return instance
+	}
+
+	result := applyFunction(bindMethod(init, definedIn, instance), args)
+	if IsError(result) {
+		return result
+	}
+
+	return instance
+}
+
+// bindMethod returns a copy of method whose environment has `self` bound to
+// instance, and `super` bound when the defining class has a parent.
+func bindMethod(
+	method *object.Function,
+	definedIn *object.Class,
+	instance *object.Instance,
+) *object.Function {
+	env := object.NewEnclosedEnvironment(method.Env)
+	env.Set("self", instance)
+
+	if definedIn.Superclass != nil {
+		env.Set("super", &object.Super{Instance: instance, Class: definedIn.Superclass})
+	}
+
+	return &object.Function{
+		Parameters:     method.Parameters,
+		ParameterTypes: method.ParameterTypes,
+		ReturnType:     method.ReturnType,
+		Body:           method.Body,
+		Env:            env,
+		Generator:      method.Generator,
+	}
+}
+
+func evalMemberExpression(obj object.Object, name string) object.Object {
+	switch obj := obj.(type) {
+	case *object.Instance:
+		if value, ok := obj.Fields[name]; ok {
+			return value
+		}
+
+		if method, definedIn := obj.Class.FindMethod(name); method != nil {
+			return bindMethod(method, definedIn, obj)
+		}
+
+		return NewError("unknown property: %s.%s", obj.Class.Name, name)
+
+	case *object.Super:
+		if method, definedIn := obj.Class.FindMethod(name); method != nil {
+			return bindMethod(method, definedIn, obj.Instance)
+		}
+
+		return NewError("unknown property: super.%s", name)
+
+	case *object.Enum:
+		return evalEnumMember(obj, name)
+
+	case *object.EnumValue:
+		return evalEnumValueMember(obj, name)
+
+	case *object.ErrorValue:
+		return evalErrorValueMember(obj, name)
+
+	case *object.Module:
+		if value, ok := obj.Get(name); ok {
+			return value
+		}
+
+		return NewError("module %s does not export %s", obj.Name, name)
+
+	default:
+		return NewError("property access not supported: %s", obj.Type())
+	}
+}
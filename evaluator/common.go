@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/Devashish08/InterPreter-Compiler/object"
+	"github.com/Devashish08/InterPreter-Compiler/token"
 )
 
 var (
@@ -12,14 +13,41 @@ var (
 	FALSE = &object.Boolean{Value: false}
 )
 
+// RuntimeErrorKind is the kind of errors raised by the interpreter itself.
+const RuntimeErrorKind = "RuntimeError"
+
 func NewError(format string, a ...interface{}) *object.Error {
-	return &object.Error{Message: fmt.Sprintf(format, a...)}
+	return &object.Error{Kind: RuntimeErrorKind, Message: fmt.Sprintf(format, a...)}
 }
 
 func IsError(obj object.Object) bool {
 	return obj != nil && obj.Type() == object.ERROR_OBJ
 }
 
+// declare binds name in env for a let or const statement, a class, an
+// enum or an import. Every binding form goes through it so that none can
+// replace a constant declared in the same scope.
+func declare(env *object.Environment, name string, val object.Object, constant bool) object.Object {
+	if env.IsConst(name) {
+		return NewError("cannot redeclare constant %s", name)
+	}
+
+	if constant {
+		return env.SetConst(name, val)
+	}
+	return env.Set(name, val)
+}
+
+// withPosition records where an error was raised. Errors keep the position
+// of the innermost node that produced them.
+func withPosition(obj object.Object, tok token.Token) object.Object {
+	if err, ok := obj.(*object.Error); ok && err.Line == 0 {
+		err.Line = tok.Line
+		err.Column = tok.Column
+	}
+	return obj
+}
+
 func IsTruthy(obj object.Object) bool {
 	switch obj {
 	case nil:
@@ -0,0 +1,92 @@
+package evaluator
+
+import (
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/object"
+)
+
+func evalArrayComprehension(
+	node *ast.ArrayComprehension,
+	env *object.Environment,
+) object.Object {
+	elements := []object.Object{}
+
+	err := evalComprehensionClause(node.Clause, env, func(scope *object.Environment) object.Object {
+		element := Eval(node.Element, scope)
+		if IsError(element) {
+			return element
+		}
+
+		elements = append(elements, element)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return &object.Array{Elements: elements}
+}
+
+func evalHashComprehension(
+	node *ast.HashComprehension,
+	env *object.Environment,
+) object.Object {
+	hash := object.NewHash()
+
+	err := evalComprehensionClause(node.Clause, env, func(scope *object.Environment) object.Object {
+		key := Eval(node.Key, scope)
+		if IsError(key) {
+			return key
+		}
+
+		hashKey, ok := key.(object.Hashable)
+		if !ok {
+			return NewError("unusable as hash key: %s", key.Type())
+		}
+
+		value := Eval(node.Value, scope)
+		if IsError(value) {
+			return value
+		}
+
+		hash.Set(hashKey, value)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return hash
+}
+
+// evalComprehensionClause calls produce once for every element of the
+// clause's iterable that passes its filter. Each call gets a new scope
+// enclosed by env holding the loop variables, so they never leak out of
+// the comprehension. It returns the first error raised.
+func evalComprehensionClause(
+	clause *ast.ComprehensionClause,
+	env *object.Environment,
+	produce func(scope *object.Environment) object.Object,
+) object.Object {
+	iterable := Eval(clause.Iterable, env)
+	if IsError(iterable) {
+		return iterable
+	}
+
+	return iterate(iterable, func(key, value object.Object) object.Object {
+		scope := object.NewEnclosedEnvironment(env)
+		bindLoopVariables(scope, clause.Key, clause.Value, iterable, key, value)
+
+		if clause.Condition != nil {
+			condition := Eval(clause.Condition, scope)
+			if IsError(condition) {
+				return condition
+			}
+			if !IsTruthy(condition) {
+				return nil
+			}
+		}
+
+		return produce(scope)
+	})
+}
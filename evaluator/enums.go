@@ -0,0 +1,83 @@
+package evaluator
+
+import (
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/object"
+)
+
+func evalEnumStatement(
+	node *ast.EnumStatement,
+	env *object.Environment,
+) object.Object {
+	enum := &object.Enum{
+		Name:     node.Name.Value,
+		Variants: make(map[string]*object.EnumVariant),
+	}
+
+	for _, v := range node.Variants {
+		variant := &object.EnumVariant{Enum: enum, Name: v.Name.Value}
+
+		for _, field := range v.Fields {
+			variant.Fields = append(variant.Fields, field.Value)
+		}
+
+		if len(variant.Fields) == 0 {
+			variant.Value = &object.EnumValue{Variant: variant}
+		}
+
+		enum.Variants[variant.Name] = variant
+	}
+
+	return declare(env, node.Name.Value, enum, false)
+}
+
+func constructEnumValue(
+	variant *object.EnumVariant,
+	args []object.Object,
+) object.Object {
+	if len(args) != len(variant.Fields) {
+		return NewError("wrong number of arguments. got=%d, want=%d",
+			len(args), len(variant.Fields))
+	}
+
+	values := make([]object.Object, len(args))
+	copy(values, args)
+
+	return &object.EnumValue{Variant: variant, Values: values}
+}
+
+// evalEnumMember looks up a variant of enum. Variants without fields are
+// values themselves; the others are constructors.
+func evalEnumMember(enum *object.Enum, name string) object.Object {
+	variant, ok := enum.Variants[name]
+	if !ok {
+		return NewError("unknown variant: %s.%s", enum.Name, name)
+	}
+	if variant.Value != nil {
+		return variant.Value
+	}
+	return variant
+}
+
+func evalEnumValueMember(value *object.EnumValue, name string) object.Object {
+	if field, ok := value.Field(name); ok {
+		return field
+	}
+
+	return NewError("unknown field: %s.%s", value.Variant.Name, name)
+}
+
+func evalEnumInfixExpression(
+	operator string,
+	left, right object.Object,
+) object.Object {
+	switch operator {
+	case "==":
+		return NativeBoolToBooleanObject(object.Equal(left, right))
+	case "!=":
+		return NativeBoolToBooleanObject(!object.Equal(left, right))
+	default:
+		return NewError("unknown operator: %s %s %s",
+			left.Type(), operator, right.Type())
+	}
+}
@@ -16,11 +16,22 @@ Key types and functions:
   - evalProgram: Evaluates a complete program node
   - evalBlockStatement: Handles blocks of statements
   - evalPrefixExpression: Handles prefix operators (!, -)
-  - evalInfixExpression: Handles infix operators (+, -, *, /, ==, etc.)
+  - evalInfixExpression: Handles infix operators (+, -, *, /, ==, etc.),
+    including operators overloaded by class methods such as __add__
   - evalIfExpression: Implements conditional logic
   - evalIdentifier: Handles variable lookup
   - evalFunctionLiteral: Creates function objects
-  - applyFunction: Handles function calls
+  - applyFunction: Handles function calls, checking type annotations of
+    parameters and return values
+  - evalTryStatement: Catches errors raised by builtins or throw
+  - newGenerator: Runs generator functions, suspending them at each `yield`
+  - ModuleLoader: Resolves, evaluates and caches modules for `import`
+  - runDeferred: Runs `defer` expressions when a function call returns
+  - DefineMacros/ExpandMacros: Expand macro calls before evaluation
+  - evalAssignExpression: Rebinds variables, instance fields and elements
+  - evalForStatement: Loops over arrays, strings, hashes and ranges
+  - evalArrayComprehension/evalHashComprehension: Build collections from a
+    `for ... in ... if ...` clause
 
 Error handling is done through the object.Error type, with detailed error messages
 to help users identify and fix issues in their code.
@@ -28,6 +39,8 @@ to help users identify and fix issues in their code.
 package evaluator
 
 import (
+	"strings"
+
 	"github.com/Devashish08/InterPreter-Compiler/ast"
 	"github.com/Devashish08/InterPreter-Compiler/object"
 )
@@ -56,8 +69,49 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if IsError(val) {
 			return val
 		}
-		env.Set(node.Name.Value, val)
-		return val
+
+		if node.Type != nil {
+			if err := checkAnnotation("variable "+node.Name.Value, node.Type, val, env); err != nil {
+				return withPosition(err, node.Token)
+			}
+		}
+
+		// Only a function created by this statement takes its doc comment;
+		// `let g = f;` must not change the documentation of f.
+		if fn, ok := val.(*object.Function); ok && node.Doc != "" {
+			if _, ok := node.Value.(*ast.FunctionLiteral); ok {
+				fn.Doc = node.Doc
+			}
+		}
+
+		return withPosition(declare(env, node.Name.Value, val, node.IsConst()), node.Token)
+
+	case *ast.ClassStatement:
+		return withPosition(evalClassStatement(node, env), node.Token)
+
+	case *ast.EnumStatement:
+		return withPosition(evalEnumStatement(node, env), node.Token)
+
+	case *ast.TryStatement:
+		return evalTryStatement(node, env)
+
+	case *ast.ThrowStatement:
+		return evalThrowStatement(node, env)
+
+	case *ast.DeferStatement:
+		return withPosition(evalDeferStatement(node, env), node.Token)
+
+	case *ast.ForStatement:
+		return withPosition(evalForStatement(node, env), node.Token)
+
+	case *ast.YieldStatement:
+		return withPosition(evalYieldStatement(node, env), node.Token)
+
+	case *ast.ImportStatement:
+		return withPosition(evalImportStatement(node, env), node.Token)
+
+	case *ast.ExportStatement:
+		return withPosition(evalExportStatement(node, env), node.Token)
 
 	// Expressions
 	case *ast.IntegerLiteral:
@@ -74,7 +128,7 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if IsError(right) {
 			return right
 		}
-		return evalPrefixExpression(node.Operator, right)
+		return withPosition(evalPrefixExpression(node.Operator, right), node.Token)
 
 	case *ast.InfixExpression:
 		left := Eval(node.Left, env)
@@ -87,20 +141,33 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return right
 		}
 
-		return evalInfixExpression(node.Operator, left, right)
+		return withPosition(evalInfixExpression(node.Operator, left, right), node.Token)
 
 	case *ast.IfExpression:
 		return evalIfExpression(node, env)
 
 	case *ast.Identifier:
-		return evalIdentifier(node, env)
+		return withPosition(evalIdentifier(node, env), node.Token)
 
 	case *ast.FunctionLiteral:
-		params := node.Parameters
-		body := node.Body
-		return &object.Function{Parameters: params, Env: env, Body: body}
+		return &object.Function{
+			Parameters:     node.Parameters,
+			ParameterTypes: node.ParameterTypes,
+			ReturnType:     node.ReturnType,
+			Env:            env,
+			Body:           node.Body,
+			Generator:      node.Generator,
+		}
 
 	case *ast.CallExpression:
+		if node.Function.TokenLiteral() == "quote" {
+			if len(node.Arguments) != 1 {
+				return withPosition(NewError("wrong number of arguments. got=%d, want=1",
+					len(node.Arguments)), node.Token)
+			}
+			return quote(node.Arguments[0], env)
+		}
+
 		function := Eval(node.Function, env)
 		if IsError(function) {
 			return function
@@ -111,7 +178,7 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 			return args[0]
 		}
 
-		return applyFunction(function, args)
+		return withPosition(applyFunction(function, args), node.Token)
 
 	case *ast.ArrayLiteral:
 		elements := evalExpressions(node.Elements, env)
@@ -129,10 +196,32 @@ func Eval(node ast.Node, env *object.Environment) object.Object {
 		if IsError(index) {
 			return index
 		}
-		return evalIndexExpression(left, index)
+		return withPosition(evalIndexExpression(left, index), node.Token)
+
+	case *ast.SliceExpression:
+		return withPosition(evalSliceExpression(node, env), node.Token)
+
+	case *ast.RangeExpression:
+		return withPosition(evalRangeExpression(node, env), node.Token)
 
 	case *ast.HashLiteral:
-		return evalHashLiteral(node, env)
+		return withPosition(evalHashLiteral(node, env), node.Token)
+
+	case *ast.ArrayComprehension:
+		return withPosition(evalArrayComprehension(node, env), node.Token)
+
+	case *ast.HashComprehension:
+		return withPosition(evalHashComprehension(node, env), node.Token)
+
+	case *ast.MemberExpression:
+		obj := Eval(node.Object, env)
+		if IsError(obj) {
+			return obj
+		}
+		return withPosition(evalMemberExpression(obj, node.Property.Value), node.Token)
+
+	case *ast.AssignExpression:
+		return withPosition(evalAssignExpression(node, env), node.Token)
 	}
 
 	return NULL
@@ -191,10 +280,16 @@ func evalInfixExpression(
 	left, right object.Object,
 ) object.Object {
 	switch {
+	case operator == "in":
+		return evalInExpression(left, right)
+	case isOverloaded(operator, left):
+		return evalOverloadedInfixExpression(operator, left, right)
 	case left.Type() == object.INTEGER_OBJ && right.Type() == object.INTEGER_OBJ:
 		return evalIntegerInfixExpression(operator, left, right)
 	case left.Type() == object.STRING_OBJ && right.Type() == object.STRING_OBJ:
 		return evalStringInfixExpression(operator, left, right)
+	case left.Type() == object.ENUM_VALUE_OBJ && right.Type() == object.ENUM_VALUE_OBJ:
+		return evalEnumInfixExpression(operator, left, right)
 	case operator == "==":
 		return NativeBoolToBooleanObject(left == right)
 	case operator == "!=":
@@ -327,9 +422,35 @@ func evalExpressions(
 func applyFunction(fn object.Object, args []object.Object) object.Object {
 	switch fn := fn.(type) {
 	case *object.Function:
-		extendedEnv := extendFunctionEnv(fn, args)
+		if fn.Generator {
+			return newGenerator(fn, args)
+		}
+
+		extendedEnv, err := extendFunctionEnv(fn, args)
+		if err != nil {
+			return err
+		}
+
 		evaluated := Eval(fn.Body, extendedEnv)
-		return unwrapReturnValue(evaluated)
+		evaluated = runDeferred(extendedEnv.Frame(), evaluated)
+		result := unwrapReturnValue(evaluated)
+
+		if fn.ReturnType != nil && !IsError(result) {
+			returned := result
+			if returned == nil {
+				returned = NULL
+			}
+			if err := checkAnnotation("return value", fn.ReturnType, returned, fn.Env); err != nil {
+				return err
+			}
+		}
+		return result
+
+	case *object.Class:
+		return instantiateClass(fn, args)
+
+	case *object.EnumVariant:
+		return constructEnumValue(fn, args)
 
 	case *object.Builtin:
 		result := fn.Fn(args...)
@@ -343,17 +464,62 @@ func applyFunction(fn object.Object, args []object.Object) object.Object {
 	}
 }
 
+// extendFunctionEnv binds the arguments of a call to fn, checking them
+// against the parameter annotations. A parameter with no argument is an
+// error; extra arguments are ignored.
 func extendFunctionEnv(
 	fn *object.Function,
 	args []object.Object,
-) *object.Environment {
-	env := object.NewEnclosedEnvironment(fn.Env)
+) (*object.Environment, *object.Error) {
+	if len(args) < len(fn.Parameters) {
+		return nil, NewError("wrong number of arguments. got=%d, want=%d",
+			len(args), len(fn.Parameters))
+	}
+
+	env := object.NewFunctionEnvironment(fn.Env)
 
 	for paramIdx, param := range fn.Parameters {
+		if fn.ParameterTypes != nil {
+			typ := fn.ParameterTypes[paramIdx]
+			if typ != nil {
+				err := checkAnnotation("parameter "+param.Value, typ, args[paramIdx], fn.Env)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
 		env.Set(param.Value, args[paramIdx])
 	}
 
-	return env
+	return env, nil
+}
+
+func evalDeferStatement(
+	node *ast.DeferStatement,
+	env *object.Environment,
+) object.Object {
+	frame := env.Frame()
+	if frame == nil {
+		return NewError("defer outside of function")
+	}
+
+	frame.Defer(object.Deferred{Expression: node.Expression, Env: env})
+	return NULL
+}
+
+// runDeferred evaluates the frame's deferred expressions in LIFO order. An
+// error raised by one of them replaces the call's result unless the call
+// already failed.
+func runDeferred(frame *object.Frame, result object.Object) object.Object {
+	for _, d := range frame.Deferred() {
+		evaluated := Eval(d.Expression, d.Env)
+		if IsError(evaluated) && !IsError(result) {
+			result = evaluated
+		}
+	}
+
+	return result
 }
 
 func unwrapReturnValue(obj object.Object) object.Object {
@@ -368,8 +534,14 @@ func evalIndexExpression(left, index object.Object) object.Object {
 	switch {
 	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
 		return evalArrayIndexExpression(left, index)
+	case left.Type() == object.STRING_OBJ && index.Type() == object.INTEGER_OBJ:
+		return evalStringIndexExpression(left, index)
+	case left.Type() == object.RANGE_OBJ && index.Type() == object.INTEGER_OBJ:
+		return evalRangeIndexExpression(left, index)
 	case left.Type() == object.HASH_OBJ:
 		return evalHashIndexExpression(left, index)
+	case left.Type() == object.INSTANCE_OBJ:
+		return evalInstanceIndexExpression(left, index)
 	default:
 		return NewError("index operator not supported: %s", left.Type())
 	}
@@ -377,24 +549,48 @@ func evalIndexExpression(left, index object.Object) object.Object {
 
 func evalArrayIndexExpression(array, index object.Object) object.Object {
 	arrayObject := array.(*object.Array)
-	idx := index.(*object.Integer).Value
-	max := int64(len(arrayObject.Elements) - 1)
 
-	if idx < 0 || idx > max {
+	idx, ok := normalizeIndex(index.(*object.Integer).Value, len(arrayObject.Elements))
+	if !ok {
 		return NULL
 	}
 
 	return arrayObject.Elements[idx]
 }
 
+func evalStringIndexExpression(str, index object.Object) object.Object {
+	value := str.(*object.String).Value
+
+	idx, ok := normalizeIndex(index.(*object.Integer).Value, len(value))
+	if !ok {
+		return NULL
+	}
+
+	return &object.String{Value: value[idx : idx+1]}
+}
+
+// normalizeIndex resolves a negative index against the end of a sequence
+// of the given length and reports whether the result is in range.
+func normalizeIndex(idx int64, length int) (int64, bool) {
+	if idx < 0 {
+		idx += int64(length)
+	}
+
+	if idx < 0 || idx >= int64(length) {
+		return 0, false
+	}
+
+	return idx, true
+}
+
 func evalHashLiteral(
 	node *ast.HashLiteral,
 	env *object.Environment,
 ) object.Object {
-	pairs := make(map[object.HashKey]object.HashPair)
+	hash := object.NewHash()
 
-	for keyNode, valueNode := range node.Pairs {
-		key := Eval(keyNode, env)
+	for _, pair := range node.Pairs {
+		key := Eval(pair.Key, env)
 		if IsError(key) {
 			return key
 		}
@@ -404,16 +600,149 @@ func evalHashLiteral(
 			return NewError("unusable as hash key: %s", key.Type())
 		}
 
-		value := Eval(valueNode, env)
+		value := Eval(pair.Value, env)
 		if IsError(value) {
 			return value
 		}
 
-		hashed := hashKey.HashKey()
-		pairs[hashed] = object.HashPair{Key: key, Value: value}
+		hash.Set(hashKey, value)
 	}
 
-	return &object.Hash{Pairs: pairs}
+	return hash
+}
+
+func evalAssignExpression(
+	node *ast.AssignExpression,
+	env *object.Environment,
+) object.Object {
+	switch target := node.Target.(type) {
+	case *ast.Identifier:
+		scope, ok := env.Resolve(target.Value)
+		if !ok {
+			return NewError("identifier not found: " + target.Value)
+		}
+
+		if scope.IsConst(target.Value) {
+			return NewError("cannot assign to constant %s", target.Value)
+		}
+
+		value := Eval(node.Value, env)
+		if IsError(value) {
+			return value
+		}
+
+		if node.Operator != "=" {
+			current, _ := scope.Get(target.Value)
+			value = evalCompoundAssignment(node.Operator, current, value)
+			if IsError(value) {
+				return value
+			}
+		}
+
+		return scope.Set(target.Value, value)
+
+	case *ast.MemberExpression:
+		obj := Eval(target.Object, env)
+		if IsError(obj) {
+			return obj
+		}
+
+		instance, ok := obj.(*object.Instance)
+		if !ok {
+			return NewError("property assignment not supported: %s", obj.Type())
+		}
+
+		value := Eval(node.Value, env)
+		if IsError(value) {
+			return value
+		}
+
+		if node.Operator != "=" {
+			current := evalMemberExpression(instance, target.Property.Value)
+			if IsError(current) {
+				return current
+			}
+			value = evalCompoundAssignment(node.Operator, current, value)
+			if IsError(value) {
+				return value
+			}
+		}
+
+		instance.Fields[target.Property.Value] = value
+		return value
+
+	case *ast.IndexExpression:
+		left := Eval(target.Left, env)
+		if IsError(left) {
+			return left
+		}
+		index := Eval(target.Index, env)
+		if IsError(index) {
+			return index
+		}
+
+		value := Eval(node.Value, env)
+		if IsError(value) {
+			return value
+		}
+
+		if node.Operator != "=" {
+			current := evalIndexExpression(left, index)
+			if IsError(current) {
+				return current
+			}
+			value = evalCompoundAssignment(node.Operator, current, value)
+			if IsError(value) {
+				return value
+			}
+		}
+
+		return evalIndexAssignment(left, index, value)
+
+	default:
+		return NewError("invalid assignment target: %s", node.Target.String())
+	}
+}
+
+// evalCompoundAssignment applies the infix operator behind +=, -=, *= or /=.
+func evalCompoundAssignment(
+	operator string,
+	current, value object.Object,
+) object.Object {
+	return evalInfixExpression(strings.TrimSuffix(operator, "="), current, value)
+}
+
+func evalIndexAssignment(left, index, value object.Object) object.Object {
+	switch {
+	case left.Type() == object.ARRAY_OBJ && index.Type() == object.INTEGER_OBJ:
+		arrayObject := left.(*object.Array)
+		if arrayObject.Frozen {
+			return NewError("cannot modify frozen ARRAY")
+		}
+
+		idx, ok := normalizeIndex(index.(*object.Integer).Value, len(arrayObject.Elements))
+		if !ok {
+			return NewError("index out of range: %d", index.(*object.Integer).Value)
+		}
+
+		arrayObject.Elements[idx] = value
+		return value
+	case left.Type() == object.HASH_OBJ:
+		hashObject := left.(*object.Hash)
+		if hashObject.Frozen {
+			return NewError("cannot modify frozen HASH")
+		}
+
+		key, ok := index.(object.Hashable)
+		if !ok {
+			return NewError("unusable as hash key: %s", index.Type())
+		}
+
+		hashObject.Set(key, value)
+		return value
+	default:
+		return NewError("index assignment not supported: %s", left.Type())
+	}
 }
 
 func evalHashIndexExpression(hash, index object.Object) object.Object {
@@ -424,10 +753,10 @@ func evalHashIndexExpression(hash, index object.Object) object.Object {
 		return NewError("unusable as hash key: %s", index.Type())
 	}
 
-	pair, ok := hashObject.Pairs[key.HashKey()]
+	value, ok := hashObject.Get(key)
 	if !ok {
 		return NULL
 	}
 
-	return pair.Value
+	return value
 }
@@ -1,6 +1,8 @@
 package evaluator
 
 import (
+	"runtime"
+	"strings"
 	"testing"
 
 	"github.com/Devashish08/InterPreter-Compiler/lexer"
@@ -194,6 +196,10 @@ func TestErrorHandling(t *testing.T) {
 			`"Hello" - "World"`,
 			"unknown operator: STRING - STRING",
 		},
+		{
+			`"Hello" == "Hello"`,
+			"unknown operator: STRING == STRING",
+		},
 		{
 			"if (10 > 1) { true + false; }",
 			"unknown operator: BOOLEAN + BOOLEAN",
@@ -222,6 +228,50 @@ if (10 > 1) {
 			`999[1]`,
 			"index operator not supported: INTEGER",
 		},
+		{
+			"x = 5;",
+			"identifier not found: x",
+		},
+		{
+			"let a = [1]; a[3] = 5;",
+			"index out of range: 3",
+		},
+		{
+			"class A {} A().foo;",
+			"unknown property: A.foo",
+		},
+		{
+			"let x = 5; x.foo;",
+			"property access not supported: INTEGER",
+		},
+		{
+			"let B = 1; class A extends B {}",
+			"superclass must be a CLASS, got INTEGER",
+		},
+		{
+			`"a" - "b"`,
+			"unknown operator: STRING - STRING",
+		},
+		{
+			"defer puts(1);",
+			"defer outside of function",
+		},
+		{
+			"enum Shape { Circle(r) } Shape.Square;",
+			"unknown variant: Shape.Square",
+		},
+		{
+			"enum Shape { Circle(r) } Shape.Circle(1).w;",
+			"unknown field: Circle.w",
+		},
+		{
+			"enum Shape { Circle(r) } Shape.Circle(1, 2);",
+			"wrong number of arguments. got=2, want=1",
+		},
+		{
+			"enum Shape { Circle(r) } Shape.Circle(1) + Shape.Circle(2);",
+			"unknown operator: ENUM_VALUE + ENUM_VALUE",
+		},
 	}
 
 	for _, tt := range tests {
@@ -257,6 +307,57 @@ func TestLetStatements(t *testing.T) {
 	}
 }
 
+func TestConstStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"const a = 5; a;", 5},
+		{"const a = 5; let b = a * 2; b;", 10},
+		{"const a = 5; let f = fn() { let a = 6; a }; f() + a;", 11},
+		{"const a = 5; let f = fn() { const a = 6; a }; f();", 6},
+		{"const a = 5; let f = fn(a) { a = a + 1; a }; f(1);", 2},
+		{"let a = 1; a = 2; const a = 3; a;", 3},
+		{"const a = 5; a = 6;", "cannot assign to constant a"},
+		{"const a = 5; a += 1;", "cannot assign to constant a"},
+		{"const a = 5; let f = fn() { a = 6; }; f();", "cannot assign to constant a"},
+		{"const a = 5; let a = 6;", "cannot redeclare constant a"},
+		{"const a = 5; const a = 6;", "cannot redeclare constant a"},
+		{"const A = 1; class A {}", "cannot redeclare constant A"},
+		{"const E = 2; enum E { X }", "cannot redeclare constant E"},
+		{"const A = 1; class A {}; A;", "cannot redeclare constant A"},
+		{"const A = 1; let f = fn() { class A {}; A }; f(); A;", 1},
+		{"class A {}; const A = 1; A;", 1},
+		{"enum E { X }; const E = 2; let E = 3;", "cannot redeclare constant E"},
+		{"const a = [1, 2]; a[0] = 3; a[0];", 3},
+		{"const a = freeze([1, 2]); a[0] = 3;", "cannot modify frozen ARRAY"},
+		{`const a = freeze({"k": 1}); a["j"] = 3;`, "cannot modify frozen HASH"},
+		{`const a = freeze({"k": [1]}); a["k"][0] = 3;`, "cannot modify frozen ARRAY"},
+		{`const a = freeze([{"k": 1}]); a[0]["k"] = 3;`, "cannot modify frozen HASH"},
+		{"let a = [1]; a[0] = a; freeze(a); len(a);", 1},
+		{"const a = freeze([1, 2]); let b = push(a, 3); b[0] = 9; b[0];", 9},
+		{"freeze(5)", 5},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("no error object returned for %q. got=%T(%+v)", tt.input, evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		}
+	}
+}
+
 func TestFunctionObject(t *testing.T) {
 	input := "fn(x) { x + 2; };"
 
@@ -300,6 +401,49 @@ func TestFunctionApplication(t *testing.T) {
 	}
 }
 
+func TestFunctionArguments(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let add = fn(x, y) { x + y; }; add(1, 2, 3);", 3},
+		{"let f = fn() { 7 }; f(1);", 7},
+		{"let f = fn(a, b) { a }; f(1);", "wrong number of arguments. got=1, want=2"},
+		{"let f = fn(a) { a }; f();", "wrong number of arguments. got=0, want=1"},
+		{"let f = fn(a: int, b: int) { a }; f(1);", "wrong number of arguments. got=1, want=2"},
+		{"class P { init(x, y) { self.x = x; } }; P(1);", "wrong number of arguments. got=1, want=2"},
+		{"class P { m(a) { a } }; P().m();", "wrong number of arguments. got=0, want=1"},
+		{"let g = fn(a) { yield a; }; g();", "wrong number of arguments. got=0, want=1"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("no error object returned for %q. got=%T(%+v)", tt.input, evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		}
+	}
+
+	evaluated := testEval("let f = fn(a, b) { a };\nf(1);")
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+	}
+	if errObj.Line != 2 || errObj.Column != 2 {
+		t.Errorf("wrong position. want=2:2, got=%d:%d", errObj.Line, errObj.Column)
+	}
+}
+
 func TestEnclosingEnvironments(t *testing.T) {
 	input := `
 let first = 10;
@@ -357,6 +501,30 @@ func TestStringConcatenation(t *testing.T) {
 	}
 }
 
+func TestBuiltinArities(t *testing.T) {
+	for name, builtin := range GetBuiltins() {
+		if name == "puts" {
+			continue // accepts any number of arguments, and prints them
+		}
+
+		for n := 0; n <= 3; n++ {
+			args := make([]object.Object, n)
+			for i := range args {
+				args[i] = NULL
+			}
+
+			result := builtin.Fn(args...)
+			err, ok := result.(*object.Error)
+			rejected := ok && strings.HasPrefix(err.Message, "wrong number of arguments")
+
+			if rejected == builtin.AcceptsArgs(n) {
+				t.Errorf("%s with %d arguments: AcceptsArgs=%t, but the call returned %v",
+					name, n, builtin.AcceptsArgs(n), result)
+			}
+		}
+	}
+}
+
 func TestBuiltinFunctions(t *testing.T) {
 	tests := []struct {
 		input    string
@@ -380,6 +548,8 @@ func TestBuiltinFunctions(t *testing.T) {
 		{`rest([])`, nil},
 		{`push([], 1)`, []int{1}},
 		{`push(1, 1)`, "argument to `push` must be ARRAY, got INTEGER"},
+		{`help(fn() {})`, nil},
+		{`help(len)`, "argument to `help` must be FUNCTION, got BUILTIN"},
 	}
 
 	for _, tt := range tests {
@@ -483,6 +653,14 @@ func TestArrayIndexExpressions(t *testing.T) {
 		},
 		{
 			"[1, 2, 3][-1]",
+			3,
+		},
+		{
+			"[1, 2, 3][-3]",
+			1,
+		},
+		{
+			"[1, 2, 3][-4]",
 			nil,
 		},
 	}
@@ -498,6 +676,108 @@ func TestArrayIndexExpressions(t *testing.T) {
 	}
 }
 
+func TestStringIndexExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`"abc"[0]`, "a"},
+		{`"abc"[2]`, "c"},
+		{`"abc"[-1]`, "c"},
+		{`"abc"[3]`, nil},
+		{`"abc"[-4]`, nil},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		expected, ok := tt.expected.(string)
+		if !ok {
+			testNullObject(t, evaluated)
+			continue
+		}
+
+		str, ok := evaluated.(*object.String)
+		if !ok {
+			t.Errorf("object is not String. got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+		if str.Value != expected {
+			t.Errorf("String has wrong value. want=%q, got=%q", expected, str.Value)
+		}
+	}
+}
+
+func TestSliceExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"[1, 2, 3, 4, 5][1:3]", []int{2, 3}},
+		{"[1, 2, 3, 4, 5][:-1]", []int{1, 2, 3, 4}},
+		{"[1, 2, 3, 4, 5][::2]", []int{1, 3, 5}},
+		{"[1, 2, 3, 4, 5][-2:]", []int{4, 5}},
+		{"[1, 2, 3, 4, 5][:]", []int{1, 2, 3, 4, 5}},
+		{"[1, 2, 3, 4, 5][::-1]", []int{5, 4, 3, 2, 1}},
+		{"[1, 2, 3, 4, 5][4:0:-2]", []int{5, 3}},
+		{"[1, 2, 3, 4, 5][-100:2]", []int{1, 2}},
+		{"[1, 2, 3, 4, 5][3:100]", []int{4, 5}},
+		{"[1, 2, 3, 4, 5][10:]", []int{}},
+		{"[1, 2, 3, 4, 5][3:1]", []int{}},
+		{"[][0:5]", []int{}},
+		{`"Hello, World"[0:5]`, "Hello"},
+		{`"Hello, World"[-5:]`, "World"},
+		{`"abc"[::-1]`, "cba"},
+		{`"abc"[5:10]`, ""},
+		{"[1, 2, 3][1::9223372036854775807]", []int{2}},
+		{"[1, 2, 3][::9223372036854775807]", []int{1}},
+		{"[1, 2, 3][1::-9223372036854775807]", []int{2}},
+		{"[1, 2, 3][::-9223372036854775807 - 1]", []int{3}},
+		{"[1, 2, 3][-9223372036854775807 - 1:9223372036854775807:2]", []int{1, 3}},
+		{`"abc"[::9223372036854775807]`, "a"},
+		{`"abc"[::-9223372036854775807 - 1]`, "c"},
+		{"[1, 2][::0]", "slice step cannot be zero"},
+		{`[1, 2]["a":]`, "slice bounds must be INTEGER, got STRING"},
+		{`[1, 2][::true]`, "slice step must be INTEGER, got BOOLEAN"},
+		{"5[1:2]", "slice operator not supported: INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case []int:
+			array, ok := evaluated.(*object.Array)
+			if !ok {
+				t.Errorf("obj not Array. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+
+			if len(array.Elements) != len(expected) {
+				t.Errorf("wrong num of elements for %s. want=%d, got=%d",
+					tt.input, len(expected), len(array.Elements))
+				continue
+			}
+
+			for i, expectedElem := range expected {
+				testIntegerObject(t, array.Elements[i], int64(expectedElem))
+			}
+		case string:
+			switch result := evaluated.(type) {
+			case *object.String:
+				if result.Value != expected {
+					t.Errorf("String has wrong value. want=%q, got=%q", expected, result.Value)
+				}
+			case *object.Error:
+				if result.Message != expected {
+					t.Errorf("wrong error message. expected=%q, got=%q", expected, result.Message)
+				}
+			default:
+				t.Errorf("object is not String or Error. got=%T (%+v)", evaluated, evaluated)
+			}
+		}
+	}
+}
+
 func TestHashLiterals(t *testing.T) {
 	input := `let two = "two";
 	{
@@ -515,26 +795,41 @@ func TestHashLiterals(t *testing.T) {
 		t.Fatalf("Eval didn't return Hash. got=%T (%+v)", evaluated, evaluated)
 	}
 
-	expected := map[object.HashKey]int64{
-		(&object.String{Value: "one"}).HashKey():   1,
-		(&object.String{Value: "two"}).HashKey():   2,
-		(&object.String{Value: "three"}).HashKey(): 3,
-		(&object.Integer{Value: 4}).HashKey():      4,
-		TRUE.HashKey():                             5,
-		FALSE.HashKey():                            6,
+	expected := []expectedPair{
+		{&object.String{Value: "one"}, 1},
+		{&object.String{Value: "two"}, 2},
+		{&object.String{Value: "three"}, 3},
+		{&object.Integer{Value: 4}, 4},
+		{TRUE, 5},
+		{FALSE, 6},
 	}
 
-	if len(result.Pairs) != len(expected) {
-		t.Fatalf("Hash has wrong num of pairs. got=%d", len(result.Pairs))
+	testHashPairs(t, result, expected)
+}
+
+type expectedPair struct {
+	key   object.Hashable
+	value int64
+}
+
+// testHashPairs checks the pairs of hash, including their order.
+func testHashPairs(t *testing.T, hash *object.Hash, expected []expectedPair) {
+	t.Helper()
+
+	pairs := hash.Pairs()
+	if len(pairs) != len(expected) {
+		t.Fatalf("Hash has wrong num of pairs. got=%d", len(pairs))
 	}
 
-	for expectedKey, expectedValue := range expected {
-		pair, ok := result.Pairs[expectedKey]
-		if !ok {
-			t.Errorf("no pair for given key in Pairs")
+	for i, tt := range expected {
+		key, ok := pairs[i].Key.(object.Hashable)
+		if !ok || key.HashKey() != tt.key.HashKey() {
+			t.Errorf("pairs[%d] has wrong key. want=%s, got=%s",
+				i, tt.key.Inspect(), pairs[i].Key.Inspect())
+			continue
 		}
 
-		testIntegerObject(t, pair.Value, expectedValue)
+		testIntegerObject(t, pairs[i].Value, tt.value)
 	}
 }
 
@@ -583,6 +878,801 @@ func TestHashIndexExpressions(t *testing.T) {
 		}
 	}
 }
+func TestAssignExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{"let a = 5; a = 10; a;", 10},
+		{"let a = 5; a += 10; a;", 15},
+		{"let a = 5; a -= 10; a;", -5},
+		{"let a = 5; a *= 2; a;", 10},
+		{"let a = 5; a /= 5; a;", 1},
+		{"let a = 1; let b = 2; a = b = 3; a + b;", 6},
+		{"let a = 1; let set = fn() { a = 2; }; set(); a;", 2},
+		{"let a = 1; let set = fn() { let a = 5; a = 2; }; set(); a;", 1},
+		{"let arr = [1, 2, 3]; arr[1] = 5; arr[1];", 5},
+		{"let arr = [1, 2, 3]; arr[2] += 5; arr[2];", 8},
+		{"let arr = [1, 2, 3]; arr[-1] = 7; arr[2];", 7},
+		{`let h = {"a": 1}; h["b"] = 2; h["b"];`, 2},
+		{`let h = {"a": 1}; h["a"] += 2; h["a"];`, 3},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestClasses(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected int64
+	}{
+		{
+			`class Counter { init(n) { self.n = n } inc() { self.n += 1 } }
+			let c = Counter(5); c.inc(); c.inc(); c.n;`,
+			7,
+		},
+		{
+			`class Point { init(x, y) { self.x = x; self.y = y; } sum() { self.x + self.y } }
+			Point(3, 4).sum();`,
+			7,
+		},
+		{
+			`class Empty {} let e = Empty(); e.value = 3; e.value;`,
+			3,
+		},
+		{
+			`class Counter { init(n) { self.n = n } inc() { self.n += 1 } }
+			let c = Counter(1); let inc = c.inc; inc(); inc(); c.n;`,
+			3,
+		},
+		{
+			`class Animal { init(legs) { self.legs = legs } describe() { self.legs } }
+			class Bird extends Animal { init() { super.init(2) } }
+			Bird().describe();`,
+			2,
+		},
+		{
+			`class A { value() { 1 } }
+			class B extends A { value() { super.value() + 10 } }
+			class C extends B { value() { super.value() + 100 } }
+			C().value();`,
+			111,
+		},
+	}
+
+	for _, tt := range tests {
+		testIntegerObject(t, testEval(tt.input), tt.expected)
+	}
+}
+
+func TestEnums(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"enum Shape { Circle(r), Rect(w, h) } Shape.Rect(2, 3).h;", 3},
+		{"enum Shape { Circle(r), Rect(w, h) } Shape.Circle(2) == Shape.Circle(2);", true},
+		{"enum Shape { Circle(r), Rect(w, h) } Shape.Circle(2) == Shape.Circle(3);", false},
+		{"enum Shape { Circle(r), Rect(w, h) } Shape.Circle(2) != Shape.Rect(2, 2);", true},
+		{"enum Color { Red, Green } Color.Red == Color.Red;", true},
+		{"enum Color { Red, Green } Color.Red == Color.Green;", false},
+		{"enum A { X } enum B { X } A.X == B.X;", false},
+		{"enum Opt { Some(v), None } Opt.Some([1, 2]) == Opt.Some([1, 2]);", true},
+		{`enum Opt { Some(v), None } {"Some": 1, "None": 2}[variant(Opt.Some(1))];`, 1},
+		{`enum Opt { Some(v), None } {Opt.Some(1): 5}[Opt.Some(1)];`, 5},
+		{`enum Opt { Some(v), None } {Opt.None: 5}[Opt.None];`, 5},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		}
+	}
+}
+
+func TestEnumInspect(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`enum Shape { Circle(r), Empty } Shape.Circle("big");`, "Shape.Circle(big)"},
+		{`enum Shape { Circle(r), Empty } Shape.Empty;`, "Shape.Empty"},
+		{`enum Shape { Circle(r), Empty } Shape.Circle;`, "Shape.Circle(r)"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("Inspect() wrong. want=%q, got=%q", tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestTryCatch(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`let r = 0; try { r = 1; } catch (e) { r = 2; }; r;`, 1},
+		{`let r = 0; try { throw "boom"; r = 1; } catch (e) { r = 2; }; r;`, 2},
+		{`let r = ""; try { pop([]); } catch (e) { r = e.message; }; r;`, "cannot pop from empty array"},
+		{`let r = ""; try { pop([]); } catch (e) { r = e.kind; }; r;`, "RuntimeError"},
+		{`let r = ""; try { throw "boom"; } catch (e) { r = e.kind; }; r;`, "Error"},
+		{`let r = ""; try { throw error("ValueError", "bad"); } catch (e) { r = e.kind + ": " + e.message; }; r;`, "ValueError: bad"},
+		{`let r = 0; try { throw 42; } catch (e) { r = e.value; }; r;`, 42},
+		{`let r = 0; try { 1 } finally { r = 5; }; r;`, 5},
+		{`let r = 0; try { throw "x"; } catch { r = 1; } finally { r += 10; }; r;`, 11},
+		{`let f = fn() { try { return 1; } finally { 2; } }; f();`, 1},
+		{`let f = fn() { try { return 1; } finally { return 2; } }; f();`, 2},
+		{`let f = fn() { throw "deep"; }; let r = ""; try { f(); } catch (e) { r = e.message; }; r;`, "deep"},
+		{`let r = ""; try { try { throw "inner"; } catch (e) { throw e; } } catch (e) { r = e.message; }; r;`, "inner"},
+		{`let r = 0; try { throw "boom"; } catch (e) { r = e.line * 100 + e.column; }; r;`, 118},
+		{"let r = 0;\ntry {\n  1 + true;\n} catch (e) { r = e.line * 100 + e.column; }; r;", 305},
+		{"let E = error(\"NotFound\", \"missing\");\nlet a = 0; try { throw E; } catch (e) { a = e.line; }\n" +
+			"let b = 0;\ntry { throw E; } catch (e) { b = e.line; }\na * 10 + b", 24},
+		{"let c = 0;\ntry { try { 1 + true; } catch (e) {\n throw e; } } catch (e) { c = e.line; }; c", 2},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			str, ok := evaluated.(*object.String)
+			if !ok {
+				t.Errorf("object is not String. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+			if str.Value != expected {
+				t.Errorf("String has wrong value. want=%q, got=%q", expected, str.Value)
+			}
+		}
+	}
+}
+
+func TestUncaughtErrors(t *testing.T) {
+	tests := []struct {
+		input           string
+		expectedKind    string
+		expectedMessage string
+		expectedLine    int
+		expectedColumn  int
+	}{
+		{`throw "boom";`, "Error", "boom", 1, 1},
+		{"let x = 1;\n  x + true;", "RuntimeError", "type mismatch: INTEGER + BOOLEAN", 2, 5},
+		{`try { throw "a"; } finally { throw "b"; }`, "Error", "b", 1, 30},
+		{`try { throw "a"; } catch (e) { throw error("Custom", "c"); }`, "Custom", "c", 1, 32},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+			continue
+		}
+
+		if errObj.Kind != tt.expectedKind || errObj.Message != tt.expectedMessage {
+			t.Errorf("wrong error. want=%s: %s, got=%s: %s",
+				tt.expectedKind, tt.expectedMessage, errObj.Kind, errObj.Message)
+		}
+
+		if errObj.Line != tt.expectedLine || errObj.Column != tt.expectedColumn {
+			t.Errorf("wrong error position. want=%d:%d, got=%d:%d",
+				tt.expectedLine, tt.expectedColumn, errObj.Line, errObj.Column)
+		}
+	}
+}
+
+func TestDeferStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`let log = ""; let f = fn() { defer log += "a"; defer log += "b"; log += "body"; }; f(); log;`,
+			"bodyba",
+		},
+		{
+			`let log = ""; let f = fn() { defer log += "d"; return log += "r"; log += "x"; }; f(); log;`,
+			"rd",
+		},
+		{
+			`let log = ""; let f = fn() { defer log += "d"; throw "e"; }; try { f(); } catch { log += "c"; }; log;`,
+			"dc",
+		},
+		{
+			`let log = ""; let f = fn(x) { let y = x + "!"; defer log += y; }; f("hi"); log;`,
+			"hi!",
+		},
+		{
+			`let log = ""; let inner = fn() { defer log += "i"; }; let outer = fn() { defer log += "o"; inner(); log += "b"; }; outer(); log;`,
+			"ibo",
+		},
+		{
+			`let fail = fn() { throw "from defer"; }; let f = fn() { defer fail(); "result" }; let r = ""; try { f(); } catch (e) { r = e.message; }; r;`,
+			"from defer",
+		},
+		{
+			`let fail = fn() { throw "from defer"; }; let f = fn() { defer fail(); throw "first"; }; let r = ""; try { f(); } catch (e) { r = e.message; }; r;`,
+			"first",
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		str, ok := evaluated.(*object.String)
+		if !ok {
+			t.Errorf("object is not String. got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+		if str.Value != tt.expected {
+			t.Errorf("String has wrong value. want=%q, got=%q", tt.expected, str.Value)
+		}
+	}
+}
+
+func TestRanges(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"len(1..5)", 4},
+		{"len(1..=5)", 5},
+		{"len(5..1)", 0},
+		{"len(0..1000000000)", 1000000000},
+		{"len(9223372036854775806..=9223372036854775807)", 2},
+		{"len(-9223372036854775807..9223372036854775807)", 9223372036854775807},
+		{"(-9223372036854775807..9223372036854775807)[-1]", 9223372036854775806},
+		{"(1..5)[0]", 1},
+		{"(1..5)[3]", 4},
+		{"(1..5)[-1]", 4},
+		{"(1..=5)[-1]", 5},
+		{"(-3..3)[1]", -2},
+		{"(1..5)[4]", nil},
+		{"(1..5)[-5]", nil},
+		{"let r = 2..4; r[1] + len(r)", 5},
+		{`"a"..3`, "range bounds must be INTEGER, got STRING"},
+		{"1..true", "range bounds must be INTEGER, got BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		default:
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestRangeInspect(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"1..5", "1..5"},
+		{"0..=-1", "0..=-1"},
+		{"let n = 3; n * 2..n * 4", "6..12"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		if _, ok := evaluated.(*object.Range); !ok {
+			t.Errorf("object is not Range. got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+		if evaluated.Inspect() != tt.expected {
+			t.Errorf("wrong Inspect. want=%q, got=%q", tt.expected, evaluated.Inspect())
+		}
+	}
+}
+
+func TestForStatements(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let total = 0; for x in [1, 2, 3] { total += x; }; total", 6},
+		{"let total = 0; for x in 1..=100 { total += x; }; total", 5050},
+		{"let total = 0; for x in 1..1 { total += 1; }; total", 0},
+		{"let total = 0; for x in 9223372036854775806..=9223372036854775807 { total += 1; }; total", 2},
+		{"let total = 0; for (i, x) in [10, 20, 30] { total += i * x; }; total", 80},
+		{"let total = 0; for (i, x) in 5..8 { total += i; }; total", 3},
+		{`let total = 0; for k in {1: "a", 2: "b"} { total += k; }; total`, 3},
+		{`let total = 0; for (k, v) in {"a": 1, "b": 2} { total += v; }; total`, 3},
+		{`let s = ""; for (k, v) in {"b": 2, "a": 1} { s += k; }; s`, "ba"},
+		{`let h = {"c": 1}; h["a"] = 2; h["c"] = 3; let s = ""; for k in h { s += k; }; s`, "ca"},
+		{`let s = ""; for c in "abc" { s = c + s; }; s`, "cba"},
+		{"let f = fn() { for x in 1..10 { if (x * x > 20) { return x; } } }; f()", 5},
+		{"let x = 42; for x in 1..3 { x; }; x", 42},
+		{"for x in 1..3 { x; }", nil},
+		{"for x in 5 { x; }", "cannot iterate over INTEGER"},
+		{"for x in 1..3 { x + true; }", "type mismatch: INTEGER + BOOLEAN"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			switch result := evaluated.(type) {
+			case *object.String:
+				if result.Value != expected {
+					t.Errorf("String has wrong value. want=%q, got=%q", expected, result.Value)
+				}
+			case *object.Error:
+				if result.Message != expected {
+					t.Errorf("wrong error message. expected=%q, got=%q", expected, result.Message)
+				}
+			default:
+				t.Errorf("object is not String or Error. got=%T (%+v)", evaluated, evaluated)
+			}
+		default:
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestInOperator(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"3 in 1..5", true},
+		{"5 in 1..5", false},
+		{"5 in 1..=5", true},
+		{"0 in 1..5", false},
+		{"9223372036854775807 in 0..=9223372036854775807", true},
+		{"9223372036854775806 in -9223372036854775807..9223372036854775807", true},
+		{"-9223372036854775807 - 1 in -9223372036854775807..9223372036854775807", false},
+		{`"a" in 1..5`, false},
+		{"2 in [1, 2, 3]", true},
+		{"4 in [1, 2, 3]", false},
+		{`[1] in [[1], [2]]`, true},
+		{`"one" in {"one": 1}`, true},
+		{`1 in {"one": 1}`, false},
+		{`"ell" in "hello"`, true},
+		{`"x" in "hello"`, false},
+		{`1 in "hello"`, "type mismatch: INTEGER in STRING"},
+		{`fn(x) { x } in {"a": 1}`, "unusable as hash key: FUNCTION"},
+		{"1 in 2", "unknown operator: INTEGER in INTEGER"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		}
+	}
+}
+
+func TestArrayComprehensions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"[x * 2 for x in [3, -1, 4, -5] if x > 0]", []int{6, 8}},
+		{"[x * x for x in 1..=4]", []int{1, 4, 9, 16}},
+		{"[i * v for (i, v) in [5, 6, 7]]", []int{0, 6, 14}},
+		{"[x for x in [] ]", []int{}},
+		{"[x for x in 1..10 if false]", []int{}},
+		{`[v for (k, v) in {"a": 1, "b": 2}]`, []int{1, 2}},
+		{"let n = 10; [x + n for x in 0..3]", []int{10, 11, 12}},
+		{"let x = 99; [x for x in 1..3]; x", 99},
+		{"[x for x in 5]", "cannot iterate over INTEGER"},
+		{"[x + true for x in 1..3]", "type mismatch: INTEGER + BOOLEAN"},
+		{"[x for x in 1..3 if y]", "identifier not found: y"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case []int:
+			array, ok := evaluated.(*object.Array)
+			if !ok {
+				t.Errorf("obj not Array. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+
+			if len(array.Elements) != len(expected) {
+				t.Errorf("wrong num of elements for %s. want=%d, got=%d",
+					tt.input, len(expected), len(array.Elements))
+				continue
+			}
+
+			for i, expectedElem := range expected {
+				testIntegerObject(t, array.Elements[i], int64(expectedElem))
+			}
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		}
+	}
+}
+
+func TestHashComprehensions(t *testing.T) {
+	input := `let h = {"one": 1, "two": 2, "three": 3};
+{k: v * 10 for (k, v) in h if v != 2}`
+
+	evaluated := testEval(input)
+	result, ok := evaluated.(*object.Hash)
+	if !ok {
+		t.Fatalf("Eval didn't return Hash. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	testHashPairs(t, result, []expectedPair{
+		{&object.String{Value: "one"}, 10},
+		{&object.String{Value: "three"}, 30},
+	})
+
+	errorTests := []struct {
+		input    string
+		expected string
+	}{
+		{"{fn(x) { x }: x for x in 1..3}", "unusable as hash key: FUNCTION"},
+		{"{x: x for x in true}", "cannot iterate over BOOLEAN"},
+	}
+
+	for _, tt := range errorTests {
+		evaluated := testEval(tt.input)
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+			continue
+		}
+		if errObj.Message != tt.expected {
+			t.Errorf("wrong error message. expected=%q, got=%q", tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestOperatorOverloading(t *testing.T) {
+	money := `
+class Money {
+	init(cents) { self.cents = cents; }
+	__add__(other) { Money(self.cents + other.cents) }
+	__sub__(other) { Money(self.cents - other.cents) }
+	__mul__(k) { Money(self.cents * k) }
+	__div__(k) { Money(self.cents / k) }
+	__eq__(other) { self.cents == other.cents }
+	__lt__(other) { self.cents < other.cents }
+	__gt__(other) { self.cents > other.cents }
+}
+class Bag {
+	init(items) { self.items = items; }
+	__index__(i) { self.items[i] }
+	__len__() { len(self.items) }
+}
+class Plain { }
+class BadLen { __len__() { "two" } }
+class BadArity {
+	__add__(a, b) { a }
+	__eq__(a, b) { true }
+	__index__() { 0 }
+	__len__(n) { n }
+}
+`
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"(Money(150) + Money(250)).cents", 400},
+		{"(Money(150) - Money(50)).cents", 100},
+		{"(Money(150) * 3).cents", 450},
+		{"(Money(150) / 2).cents", 75},
+		{"let m = Money(1); m += Money(2); m.cents", 3},
+		{"Money(5) == Money(5)", true},
+		{"Money(5) != Money(5)", false},
+		{"Money(5) != Money(6)", true},
+		{"Money(5) < Money(6)", true},
+		{"Money(5) > Money(6)", false},
+		{"Bag([4, 5, 6])[-1]", 6},
+		{"len(Bag([4, 5, 6]))", 3},
+		{"let p = Plain(); p == p", true},
+		{"Plain() == Plain()", false},
+		{"Plain() + Plain()", "unknown operator: INSTANCE + INSTANCE"},
+		{"Plain()[0]", "index operator not supported: INSTANCE"},
+		{"len(Plain())", "argument to `len` not supported, got INSTANCE"},
+		{"len(BadLen())", "BadLen.__len__ must return INTEGER, got STRING"},
+		{"1 + Money(1)", "type mismatch: INTEGER + INSTANCE"},
+		{"Money(1) + 1", "property access not supported: INTEGER"},
+		{"BadArity() + 1", "BadArity.__add__ takes 2 parameters, operator passes 1"},
+		{"BadArity() != 1", "BadArity.__eq__ takes 2 parameters, operator passes 1"},
+		{"BadArity()[0]", 0},
+		{"len(BadArity())", "BadArity.__len__ takes 1 parameter, operator passes 0"},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(money + tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case bool:
+			testBooleanObject(t, evaluated, expected)
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("no error object returned for %q. got=%T(%+v)", tt.input, evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		}
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	prelude := `
+let count = fn(n) { let i = 0; for x in 0..n { yield i; i += 1; } };
+let naturals = fn() { let i = 0; for x in 0..1000000000 { yield i; i += 1; } };
+let map = fn(f, g) { for x in g { yield f(x); } };
+`
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let g = count(2); next(g) + next(g) * 10", 10},
+		{"let g = count(1); next(g); next(g)", nil},
+		{"let g = count(1); next(g); next(g); next(g)", nil},
+		{"let total = 0; for x in count(5) { total += x; }; total", 10},
+		{"len([x * x for x in map(fn(x) { x + 1 }, count(4))])", 4},
+		{"sum([x for x in map(fn(x) { x * 2 }, count(4)) if x > 2])", 10},
+		{"let f = fn() { for n in naturals() { if (n * n > 50) { return n; } } }; f()", 8},
+		{"let total = 0; for (i, x) in count(3) { total += i; }; total", 3},
+		{"let g = naturals(); next(g); close(g); next(g)", nil},
+		{"let g = count(3); close(g); next(g)", nil},
+		{"let f = fn() { yield 1; return 5; yield 2; }; let g = f(); next(g); next(g)", nil},
+		{"let f = fn() { yield 1; throw \"boom\"; }; let g = f(); next(g); next(g)", "boom"},
+		{"let f = fn() { yield 1 + true; }; next(f())", "type mismatch: INTEGER + BOOLEAN"},
+		{"let f = fn() { yield 1; }; let g = f(); for x in g { }; next(g)", nil},
+		{"next(5)", "argument to `next` must be GENERATOR, got INTEGER"},
+		{"close([])", "argument to `close` must be GENERATOR, got ARRAY"},
+		{"class R { init(n) { self.n = n; } items() { for i in 0..self.n { yield i; } } } sum([x for x in R(4).items()])", 6},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(prelude + tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("no error object returned for %q. got=%T(%+v)", tt.input, evaluated, evaluated)
+				continue
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		default:
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestGeneratorCleanup(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{
+			`let log = ""; let g = fn() { defer log += "d"; try { yield 1; yield 2; } finally { log += "f"; } };
+let f = fn() { for x in g() { log += "x"; return x; } }; f(); log;`,
+			"xfd",
+		},
+		{
+			`let log = ""; let g = fn() { try { yield 1; } catch { log += "caught"; } finally { log += "f"; } };
+let it = g(); next(it); close(it); log;`,
+			"f",
+		},
+		{
+			`let log = ""; let g = fn() { try { yield 1; } finally { yield 2; log += "f"; } };
+let it = g(); next(it); close(it); log;`,
+			"",
+		},
+		{
+			`let log = ""; let g = fn() { defer log += "d"; yield 1; };
+for x in g() { log += "x"; }; log;`,
+			"xd",
+		},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+		str, ok := evaluated.(*object.String)
+		if !ok {
+			t.Errorf("object is not String. got=%T (%+v)", evaluated, evaluated)
+			continue
+		}
+		if str.Value != tt.expected {
+			t.Errorf("String has wrong value. want=%q, got=%q", tt.expected, str.Value)
+		}
+	}
+}
+
+func TestAbandonedGenerators(t *testing.T) {
+	var finalizers []func()
+	setFinalizer = func(obj interface{}, finalizer interface{}) {
+		gen, f := obj.(*object.Generator), finalizer.(func(*object.Generator))
+		finalizers = append(finalizers, func() { f(gen) })
+	}
+	defer func() { setFinalizer = runtime.SetFinalizer }()
+
+	env := object.NewEnvironment()
+	eval := func(input string) object.Object {
+		program := parser.New(lexer.New(input)).ParseProgram()
+		return Eval(program, env)
+	}
+
+	eval(`let log = "";
+let g = fn() { defer log += "d"; try { yield 1; yield 2; } finally { log += "f"; } };
+let suspended = g(); next(suspended);
+let unstarted = g();
+let closed = g(); next(closed); close(closed);
+let exhausted = g(); for x in exhausted {}`)
+
+	if log, _ := env.Get("log"); log.Inspect() != "fdfd" {
+		t.Fatalf("wrong log before finalizers. want=%q, got=%q", "fdfd", log.Inspect())
+	}
+
+	// A suspended generator's finalizer returns once its goroutine has
+	// exited, without running its deferred expression or finally block.
+	for _, finalize := range finalizers {
+		finalize()
+	}
+
+	if log, _ := env.Get("log"); log.Inspect() != "fdfd" {
+		t.Errorf("finalizers ran user code. want=%q, got=%q", "fdfd", log.Inspect())
+	}
+	if len(finalizers) != 4 {
+		t.Errorf("wrong number of finalizers. want=4, got=%d", len(finalizers))
+	}
+}
+
+func TestTypeAnnotations(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"let n: int = 5; n;", 5},
+		{"let f = fn(x: int, y) -> int { x + y }; f(1, 2);", 3},
+		{"let f = fn(xs: [int]) -> int { len(xs) }; f([1, 2, 3]);", 3},
+		{"let f = fn(xs: [int]) { len(xs) }; f([]);", 0},
+		{`let f = fn(h: {string: int}) { h["a"] }; f({"a": 1});`, 1},
+		{"let f = fn(x: any) { x }; f(7);", 7},
+		{"let apply = fn(g: fn, x: int) { g(x) }; apply(fn(x) { x * 2 }, 4);", 8},
+		{"class A {} class B extends A {} let f = fn(a: A) { 1 }; f(B());", 1},
+		{"enum Color { Red } let f = fn(c: Color) { 1 }; f(Color.Red);", 1},
+		{"class P { add(x: int) -> int { x + 1 } } P().add(1);", 2},
+		{`let n: int = "five";`, "variable n: expected int, got string"},
+		{`let f = fn(x: int) { x }; f("a");`, "parameter x: expected int, got string"},
+		{`let f = fn(names: [string]) { 1 }; f(["a", 2]);`, "parameter names: expected [string], got array"},
+		{`let f = fn(names: [string]) { 1 }; f([1, 2]);`, "parameter names: expected [string], got [int]"},
+		{"let f = fn() -> bool { 1 }; f();", "return value: expected bool, got int"},
+		{"let f = fn() -> int { }; f();", "return value: expected int, got null"},
+		{"class A {} let f = fn(a: A) { 1 }; f(1);", "parameter a: expected A, got int"},
+		{"class P { add(x: int) { x } } P().add(true);", "parameter x: expected int, got bool"},
+		{"let g = fn(x: int) { yield x; }; g(true);", "parameter x: expected int, got bool"},
+		{"let n: integer = 5;", "variable n: unknown type integer"},
+		{"let f = fn(xs: [integer]) { 1 }; f([1, 2]);", "parameter xs: unknown type integer"},
+		{"let f = fn(h: {str: int}) { 1 }; f({});", "parameter h: unknown type str"},
+		{"let f = fn() -> Point { 1 }; f();", "return value: unknown type Point"},
+		{"let Point = 1; let f = fn(p: Point) { 1 }; f(1);", "parameter p: unknown type Point"},
+		{"class P { m(q: Q) { 1 } } class Q {} P().m(Q());", 1},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			errObj, ok := evaluated.(*object.Error)
+			if !ok {
+				t.Errorf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+				continue
+			}
+			if errObj.Kind != TypeErrorKind {
+				t.Errorf("wrong error kind. expected=%q, got=%q", TypeErrorKind, errObj.Kind)
+			}
+			if errObj.Message != expected {
+				t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+			}
+		}
+	}
+}
+
+func TestHelp(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{"/// Adds.\n/// Really.\nlet add = fn(a, b) { a + b }; help(add);", "Adds.\nReally."},
+		{"/// Adds.\nlet add = fn(a, b) { a + b }; let plus = add; help(plus);", "Adds."},
+		{"let add = fn(a, b) { a + b }; /// Alias.\nlet plus = add; help(add);", nil},
+	}
+
+	for _, tt := range tests {
+		evaluated := testEval(tt.input)
+
+		switch expected := tt.expected.(type) {
+		case string:
+			str, ok := evaluated.(*object.String)
+			if !ok {
+				t.Errorf("object is not String. got=%T (%+v)", evaluated, evaluated)
+				continue
+			}
+			if str.Value != expected {
+				t.Errorf("String has wrong value. want=%q, got=%q", expected, str.Value)
+			}
+		case nil:
+			testNullObject(t, evaluated)
+		}
+	}
+}
+
+func TestInstanceInspect(t *testing.T) {
+	input := `class P { init(y, x) { self.y = y; self.x = x; } } P(2, 1);`
+
+	evaluated := testEval(input)
+	instance, ok := evaluated.(*object.Instance)
+	if !ok {
+		t.Fatalf("object is not Instance. got=%T (%+v)", evaluated, evaluated)
+	}
+
+	if instance.Inspect() != "P{x: 1, y: 2}" {
+		t.Errorf("instance.Inspect() wrong. got=%q", instance.Inspect())
+	}
+}
+
 func testEval(input string) object.Object {
 	l := lexer.New(input)
 	p := parser.New(l)
@@ -0,0 +1,89 @@
+package evaluator
+
+import (
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/object"
+)
+
+// UserErrorKind is the kind of errors thrown from scripts without an
+// explicit kind.
+const UserErrorKind = "Error"
+
+func evalTryStatement(
+	node *ast.TryStatement,
+	env *object.Environment,
+) object.Object {
+	result := Eval(node.Block, env)
+
+	if err, ok := result.(*object.Error); ok && node.Catch != nil && err.Kind != GeneratorExitKind {
+		catchEnv := object.NewEnclosedEnvironment(env)
+		if node.CatchParam != nil {
+			catchEnv.Set(node.CatchParam.Value, &object.ErrorValue{Error: err})
+		}
+		result = Eval(node.Catch, catchEnv)
+	}
+
+	if node.Finally != nil {
+		// An error or return from the finally block replaces the outcome
+		// of the try and catch blocks.
+		final := Eval(node.Finally, env)
+		if final != nil {
+			rt := final.Type()
+			if rt == object.RETURN_VALUE_OBJ || rt == object.ERROR_OBJ {
+				return final
+			}
+		}
+	}
+
+	if result == nil {
+		return NULL
+	}
+
+	return result
+}
+
+func evalThrowStatement(
+	node *ast.ThrowStatement,
+	env *object.Environment,
+) object.Object {
+	val := Eval(node.Value, env)
+	if IsError(val) {
+		return val
+	}
+
+	var err *object.Error
+
+	switch val := val.(type) {
+	case *object.ErrorValue:
+		// The value may be thrown again, so its error is not positioned in
+		// place. A caught error keeps the position it was first raised at.
+		copied := *val.Error
+		err = &copied
+	case *object.String:
+		err = &object.Error{Kind: UserErrorKind, Message: val.Value}
+	default:
+		err = &object.Error{Kind: UserErrorKind, Message: val.Inspect(), Value: val}
+	}
+
+	return withPosition(err, node.Token)
+}
+
+func evalErrorValueMember(ev *object.ErrorValue, name string) object.Object {
+	switch name {
+	case "message":
+		return &object.String{Value: ev.Error.Message}
+	case "kind":
+		return &object.String{Value: ev.Error.Kind}
+	case "line":
+		return &object.Integer{Value: int64(ev.Error.Line)}
+	case "column":
+		return &object.Integer{Value: int64(ev.Error.Column)}
+	case "value":
+		if ev.Error.Value == nil {
+			return NULL
+		}
+		return ev.Error.Value
+	default:
+		return NewError("unknown property: %s.%s", ev.Error.Kind, name)
+	}
+}
@@ -0,0 +1,147 @@
+package evaluator
+
+import (
+	"runtime"
+
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/object"
+)
+
+// GeneratorExitKind is the kind of the error that unwinds a generator
+// function when its generator is closed. try statements do not catch it,
+// but deferred expressions and finally blocks still run.
+const GeneratorExitKind = "GeneratorExit"
+
+// generatorMessage is sent from a running generator function to the code
+// consuming it, either for a yield or when the function finishes.
+type generatorMessage struct {
+	value object.Object
+	done  bool
+}
+
+// setFinalizer is runtime.SetFinalizer; tests replace it to run the
+// finalizers of generators without waiting for a collection.
+var setFinalizer = runtime.SetFinalizer
+
+// newGenerator prepares a call of a generator function. The body runs on
+// its own goroutine, but only while the consumer waits for it in Next or
+// Close, so evaluation never happens concurrently.
+//
+// A generator dropped before it finishes leaves its goroutine blocked in a
+// yield. Once the garbage collector finds the generator unreachable, the
+// goroutine exits without evaluating anything further, so deferred
+// expressions and finally blocks only run when a generator is exhausted or
+// closed.
+func newGenerator(fn *object.Function, args []object.Object) object.Object {
+	env, err := extendFunctionEnv(fn, args)
+	if err != nil {
+		return err
+	}
+	frame := env.Frame()
+
+	resume := make(chan bool)
+	messages := make(chan generatorMessage)
+	stopped, exited := make(chan struct{}), make(chan struct{})
+	started, finished := false, false
+
+	frame.Yield = func(value object.Object) bool {
+		messages <- generatorMessage{value: value}
+		select {
+		case ok := <-resume:
+			return ok
+		case <-stopped:
+			runtime.Goexit()
+			return false
+		}
+	}
+
+	run := func() {
+		defer close(exited)
+
+		evaluated := Eval(fn.Body, env)
+		evaluated = runDeferred(frame, evaluated)
+
+		var err object.Object
+		if IsError(evaluated) && evaluated.(*object.Error).Kind != GeneratorExitKind {
+			err = evaluated
+		}
+		messages <- generatorMessage{value: err, done: true}
+	}
+
+	receive := func() (object.Object, bool) {
+		msg := <-messages
+		if msg.done {
+			finished = true
+			return msg.value, false
+		}
+		return msg.value, true
+	}
+
+	// The closures below must not refer to gen, or it would never become
+	// unreachable.
+	gen := &object.Generator{}
+	setFinalizer(gen, func(*object.Generator) {
+		if started && !finished {
+			close(stopped)
+			<-exited
+		}
+	})
+
+	gen.Next = func() (object.Object, bool) {
+		if finished {
+			return nil, false
+		}
+
+		if !started {
+			started = true
+			go run()
+		} else {
+			resume <- true
+		}
+
+		return receive()
+	}
+
+	gen.Close = func() {
+		if finished {
+			return
+		}
+
+		if !started {
+			finished = true
+			return
+		}
+
+		// A finally block may yield again while the function unwinds; keep
+		// refusing until it finishes.
+		for {
+			resume <- false
+			if _, ok := receive(); !ok {
+				return
+			}
+		}
+	}
+
+	return gen
+}
+
+func evalYieldStatement(
+	node *ast.YieldStatement,
+	env *object.Environment,
+) object.Object {
+	frame := env.Frame()
+	if frame == nil || frame.Yield == nil {
+		return NewError("yield outside of generator")
+	}
+
+	value := Eval(node.Value, env)
+	if IsError(value) {
+		return value
+	}
+
+	if !frame.Yield(value) {
+		return &object.Error{Kind: GeneratorExitKind, Message: "generator closed"}
+	}
+
+	return NULL
+}
@@ -0,0 +1,154 @@
+package evaluator
+
+import (
+	"strings"
+
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/object"
+)
+
+// iterate calls fn for each element of an iterable value. Arrays, strings,
+// ranges and generators pass the element's index as key; hashes pass each
+// key and its value, in insertion order. Iteration stops as soon as fn returns
+// a non-nil object, which iterate returns, closing a generator first.
+// Values that cannot be iterated produce an error.
+func iterate(
+	iterable object.Object,
+	fn func(key, value object.Object) object.Object,
+) object.Object {
+	switch iterable := iterable.(type) {
+	case *object.Array:
+		for i, elem := range iterable.Elements {
+			if stop := fn(&object.Integer{Value: int64(i)}, elem); stop != nil {
+				return stop
+			}
+		}
+
+	case *object.String:
+		for i := 0; i < len(iterable.Value); i++ {
+			char := &object.String{Value: iterable.Value[i : i+1]}
+			if stop := fn(&object.Integer{Value: int64(i)}, char); stop != nil {
+				return stop
+			}
+		}
+
+	case *object.Range:
+		for i := int64(0); i < iterable.Len(); i++ {
+			value := &object.Integer{Value: iterable.At(i)}
+			if stop := fn(&object.Integer{Value: i}, value); stop != nil {
+				return stop
+			}
+		}
+
+	case *object.Generator:
+		for i := int64(0); ; i++ {
+			value, ok := iterable.Next()
+			if !ok {
+				// value is the error that ended the generator, if any.
+				return value
+			}
+
+			if stop := fn(&object.Integer{Value: i}, value); stop != nil {
+				iterable.Close()
+				return stop
+			}
+		}
+
+	case *object.Hash:
+		for _, pair := range iterable.Pairs() {
+			if stop := fn(pair.Key, pair.Value); stop != nil {
+				return stop
+			}
+		}
+
+	default:
+		return NewError("cannot iterate over %s", iterable.Type())
+	}
+
+	return nil
+}
+
+func evalForStatement(
+	node *ast.ForStatement,
+	env *object.Environment,
+) object.Object {
+	iterable := Eval(node.Iterable, env)
+	if IsError(iterable) {
+		return iterable
+	}
+
+	stop := iterate(iterable, func(key, value object.Object) object.Object {
+		loopEnv := object.NewEnclosedEnvironment(env)
+		bindLoopVariables(loopEnv, node.Key, node.Value, iterable, key, value)
+
+		result := Eval(node.Body, loopEnv)
+		if result != nil {
+			rt := result.Type()
+			if rt == object.RETURN_VALUE_OBJ || rt == object.ERROR_OBJ {
+				return result
+			}
+		}
+		return nil
+	})
+	if stop != nil {
+		return stop
+	}
+
+	return NULL
+}
+
+// bindLoopVariables binds the variables of a `for` loop or comprehension
+// for one element. The single-variable form binds the element, or the key
+// when iterating over a hash.
+func bindLoopVariables(
+	env *object.Environment,
+	keyName, valueName *ast.Identifier,
+	iterable, key, value object.Object,
+) {
+	_, isHash := iterable.(*object.Hash)
+
+	switch {
+	case keyName != nil:
+		env.Set(keyName.Value, key)
+		env.Set(valueName.Value, value)
+	case isHash:
+		env.Set(valueName.Value, key)
+	default:
+		env.Set(valueName.Value, value)
+	}
+}
+
+// evalInExpression implements the membership operator `x in y`.
+func evalInExpression(left, right object.Object) object.Object {
+	switch right := right.(type) {
+	case *object.Array:
+		for _, elem := range right.Elements {
+			if object.Equal(left, elem) {
+				return TRUE
+			}
+		}
+		return FALSE
+
+	case *object.Hash:
+		key, ok := left.(object.Hashable)
+		if !ok {
+			return NewError("unusable as hash key: %s", left.Type())
+		}
+		_, ok = right.Get(key)
+		return NativeBoolToBooleanObject(ok)
+
+	case *object.String:
+		str, ok := left.(*object.String)
+		if !ok {
+			return NewError("type mismatch: %s in %s", left.Type(), right.Type())
+		}
+		return NativeBoolToBooleanObject(strings.Contains(right.Value, str.Value))
+
+	case *object.Range:
+		n, ok := left.(*object.Integer)
+		return NativeBoolToBooleanObject(ok && right.Contains(n.Value))
+
+	default:
+		return NewError("unknown operator: %s in %s", left.Type(), right.Type())
+	}
+}
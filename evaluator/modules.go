@@ -0,0 +1,204 @@
+package evaluator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/lexer"
+	"github.com/Devashish08/InterPreter-Compiler/object"
+	"github.com/Devashish08/InterPreter-Compiler/parser"
+)
+
+// ModuleExtension is appended to import paths that have no extension.
+const ModuleExtension = ".monkey"
+
+// ModuleLoader implements object.Importer. Import paths are resolved
+// relative to the importing file first and then against each directory of
+// SearchPath. Every module is evaluated once, in its own environment, and
+// cached by its resolved path.
+type ModuleLoader struct {
+	SearchPath    []string
+	ParserOptions []parser.Option // used when parsing each module
+	// CheckNames, when set, checks the names of each module after its
+	// macros are expanded. An error stops the module before it runs.
+	CheckNames func(program *ast.Program) error
+
+	modules map[string]*object.Module
+	loading []string // resolved paths of modules being evaluated, outermost first
+}
+
+func NewModuleLoader(searchPath []string) *ModuleLoader {
+	return &ModuleLoader{
+		SearchPath: searchPath,
+		modules:    make(map[string]*object.Module),
+	}
+}
+
+// NewEnvironment creates the global environment for the program in the
+// file at path, or for the REPL when path is empty. Imports evaluated in it
+// are loaded by ml. The program counts as being loaded, so modules that
+// import it back are reported as a cycle.
+func (ml *ModuleLoader) NewEnvironment(path string) *object.Environment {
+	module := &object.Module{Name: path, Importer: ml}
+
+	if path != "" {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		module.Path = path
+		ml.loading = append(ml.loading, path)
+	}
+
+	return object.NewModuleEnvironment(module)
+}
+
+func (ml *ModuleLoader) Import(name, from string) object.Object {
+	path, ok := ml.resolve(name, from)
+	if !ok {
+		return NewError("module not found: %s", name)
+	}
+
+	for i, loading := range ml.loading {
+		if loading == path {
+			return NewError("import cycle: %s", ml.cycle(i, path))
+		}
+	}
+
+	if module, ok := ml.modules[path]; ok {
+		return module
+	}
+
+	ml.loading = append(ml.loading, path)
+	defer func() { ml.loading = ml.loading[:len(ml.loading)-1] }()
+
+	module := &object.Module{Name: name, Path: path, Importer: ml}
+	if err := ml.evalModule(module); err != nil {
+		return err
+	}
+
+	ml.modules[path] = module
+	return module
+}
+
+// resolve finds the file an import path refers to.
+func (ml *ModuleLoader) resolve(name, from string) (string, bool) {
+	if filepath.Ext(name) == "" {
+		name += ModuleExtension
+	}
+
+	dirs := []string{"."}
+	if from != "" {
+		dirs[0] = filepath.Dir(from)
+	}
+	if !filepath.IsAbs(name) {
+		dirs = append(dirs, ml.SearchPath...)
+	}
+
+	for _, dir := range dirs {
+		path := name
+		if !filepath.IsAbs(name) {
+			path = filepath.Join(dir, name)
+		}
+
+		info, err := os.Stat(path)
+		if err != nil || info.IsDir() {
+			continue
+		}
+
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		return path, true
+	}
+
+	return "", false
+}
+
+// cycle describes the chain of imports from ml.loading[start] back to
+// path, such as "a.monkey -> b.monkey -> a.monkey".
+func (ml *ModuleLoader) cycle(start int, path string) string {
+	names := []string{}
+	for _, loading := range ml.loading[start:] {
+		names = append(names, filepath.Base(loading))
+	}
+	names = append(names, filepath.Base(path))
+
+	return strings.Join(names, " -> ")
+}
+
+func (ml *ModuleLoader) evalModule(module *object.Module) *object.Error {
+	input, err := os.ReadFile(module.Path)
+	if err != nil {
+		return NewError("module %s: %s", module.Name, err)
+	}
+
+	p := parser.New(lexer.New(string(input)), ml.ParserOptions...)
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return NewError("module %s: %s", module.Name, strings.Join(p.Errors(), "; "))
+	}
+
+	macroEnv := object.NewEnvironment()
+	DefineMacros(program, macroEnv)
+	expanded, err := ExpandMacros(program, macroEnv)
+	if err != nil {
+		return NewError("module %s: %s", module.Name, err)
+	}
+
+	if ml.CheckNames != nil {
+		if err := ml.CheckNames(expanded.(*ast.Program)); err != nil {
+			return NewError("module %s: %s", module.Name, err)
+		}
+	}
+
+	env := object.NewModuleEnvironment(module)
+	if result, ok := Eval(expanded, env).(*object.Error); ok {
+		return result
+	}
+
+	return nil
+}
+
+func evalImportStatement(
+	node *ast.ImportStatement,
+	env *object.Environment,
+) object.Object {
+	module := env.Module()
+	if module == nil || module.Importer == nil {
+		return NewError("import is not supported here")
+	}
+
+	imported := module.Importer.Import(node.Path.Value, module.Path)
+	if IsError(imported) {
+		return imported
+	}
+
+	return declare(env, node.Alias.Value, imported, false)
+}
+
+func evalExportStatement(
+	node *ast.ExportStatement,
+	env *object.Environment,
+) object.Object {
+	if !env.IsTopLevel() {
+		return NewError("export is only allowed at the top level")
+	}
+
+	result := Eval(node.Statement, env)
+	if IsError(result) {
+		return result
+	}
+
+	switch stmt := node.Statement.(type) {
+	case *ast.LetStatement:
+		env.Export(stmt.Name.Value)
+	case *ast.ClassStatement:
+		env.Export(stmt.Name.Value)
+	case *ast.EnumStatement:
+		env.Export(stmt.Name.Value)
+	}
+
+	return result
+}
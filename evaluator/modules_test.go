@@ -0,0 +1,198 @@
+package evaluator
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/lexer"
+	"github.com/Devashish08/InterPreter-Compiler/object"
+	"github.com/Devashish08/InterPreter-Compiler/parser"
+)
+
+func TestImportStatements(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"lib/strings.monkey": `
+import "helpers" as h;
+export let shout = fn(s) { h.exclaim(upper(s)) };
+let hidden = 1;
+let loads = 0;
+export let count = fn() { loads };
+loads += 1;
+`,
+		"lib/helpers.monkey": `export let exclaim = fn(s) { s + "!" };`,
+		"lib/shapes.monkey": `
+export class Point { init(x) { self.x = x; } }
+export enum Color { Red, Green }
+export const ORIGIN = 0;
+`,
+		"search/util.monkey": `export let twice = fn(x) { x * 2 };`,
+	})
+
+	tests := []struct {
+		input    string
+		expected interface{}
+	}{
+		{`import "lib/strings" as s; s.shout("hi")`, "HI!"},
+		{`import "lib/strings.monkey" as s; s.shout("a")`, "A!"},
+		{`import "lib/strings" as a; import "lib/strings" as b; a.count() + b.count()`, 2},
+		{`import "lib/shapes" as sh; sh.Point(4).x + sh.ORIGIN`, 4},
+		{`import "lib/shapes" as sh; variant(sh.Color.Green)`, "Green"},
+		{`import "util" as u; u.twice(21)`, 42},
+		{`let f = fn() { import "util" as u; u.twice(2) }; f()`, 4},
+		{`import "lib/strings" as s; s.hidden`, "module lib/strings does not export hidden"},
+		{`import "lib/strings" as s; s.exclaim`, "module lib/strings does not export exclaim"},
+		{`import "missing" as m;`, "module not found: missing"},
+		{`const s = 1; import "util" as s;`, "cannot redeclare constant s"},
+	}
+
+	for _, tt := range tests {
+		loader := NewModuleLoader([]string{filepath.Join(dir, "search")})
+		evaluated := testEvalFile(t, loader, filepath.Join(dir, "main.monkey"), tt.input)
+
+		switch expected := tt.expected.(type) {
+		case int:
+			testIntegerObject(t, evaluated, int64(expected))
+		case string:
+			switch result := evaluated.(type) {
+			case *object.String:
+				if result.Value != expected {
+					t.Errorf("String has wrong value. want=%q, got=%q", expected, result.Value)
+				}
+			case *object.Error:
+				if result.Message != expected {
+					t.Errorf("wrong error message. expected=%q, got=%q", expected, result.Message)
+				}
+			default:
+				t.Errorf("object is not String or Error. got=%T (%+v)", evaluated, evaluated)
+			}
+		}
+	}
+}
+
+func TestImportErrors(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"a.monkey":      `import "b" as b; export let x = 1;`,
+		"b.monkey":      `import "a" as a; export let y = 2;`,
+		"self.monkey":   `import "self" as me;`,
+		"back.monkey":   `import "main" as m;`,
+		"main.monkey":   ``,
+		"broken.monkey": `let = 5;`,
+		"fails.monkey":  `export let x = 1; 1 + true;`,
+		"nested.monkey": `let f = fn() { export let x = 1; }; f();`,
+	})
+
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`import "a" as a;`, "import cycle: a.monkey -> b.monkey -> a.monkey"},
+		{`import "self" as s;`, "import cycle: self.monkey -> self.monkey"},
+		{`import "back" as b;`, "import cycle: main.monkey -> back.monkey -> main.monkey"},
+		{`import "broken" as b;`, "module broken: expected next token to be IDENT, got = instead"},
+		{`import "fails" as f;`, "type mismatch: INTEGER + BOOLEAN"},
+		{`import "nested" as n;`, "export is only allowed at the top level"},
+	}
+
+	for _, tt := range tests {
+		loader := NewModuleLoader(nil)
+		evaluated := testEvalFile(t, loader, filepath.Join(dir, "main.monkey"), tt.input)
+
+		errObj, ok := evaluated.(*object.Error)
+		if !ok {
+			t.Errorf("no error object returned for %q. got=%T(%+v)", tt.input, evaluated, evaluated)
+			continue
+		}
+		if !strings.HasPrefix(errObj.Message, tt.expected) {
+			t.Errorf("wrong error message. expected=%q, got=%q", tt.expected, errObj.Message)
+		}
+	}
+}
+
+func TestImportCheckNames(t *testing.T) {
+	dir := writeModules(t, map[string]string{
+		"lib.monkey": `export let x = missing;`,
+		"ok.monkey":  `export let x = 1;`,
+	})
+
+	// The resolver imports this package, so a stand-in rejects programs
+	// that mention missing.
+	var checked []string
+	loader := NewModuleLoader(nil)
+	loader.CheckNames = func(program *ast.Program) error {
+		checked = append(checked, program.String())
+		if strings.Contains(program.String(), "missing") {
+			return fmt.Errorf("identifier not found: missing")
+		}
+		return nil
+	}
+
+	evaluated := testEvalFile(t, loader, filepath.Join(dir, "main.monkey"), `import "lib" as lib;`)
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+	}
+	expected := "module lib: identifier not found: missing"
+	if errObj.Message != expected {
+		t.Errorf("wrong error message. expected=%q, got=%q", expected, errObj.Message)
+	}
+	if errObj.Line != 1 || errObj.Column != 1 {
+		t.Errorf("wrong position. want=1:1, got=%d:%d", errObj.Line, errObj.Column)
+	}
+
+	evaluated = testEvalFile(t, loader, filepath.Join(dir, "main.monkey"), `import "ok" as ok; ok.x`)
+	testIntegerObject(t, evaluated, 1)
+
+	if len(checked) != 2 {
+		t.Errorf("CheckNames called %d times, want once per module", len(checked))
+	}
+}
+
+func TestImportWithoutLoader(t *testing.T) {
+	evaluated := testEval(`import "lib/strings" as s;`)
+
+	errObj, ok := evaluated.(*object.Error)
+	if !ok {
+		t.Fatalf("no error object returned. got=%T(%+v)", evaluated, evaluated)
+	}
+	if errObj.Message != "import is not supported here" {
+		t.Errorf("wrong error message. got=%q", errObj.Message)
+	}
+}
+
+// writeModules creates the given files below a temporary directory and
+// returns the directory.
+func writeModules(t *testing.T, files map[string]string) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return dir
+}
+
+// testEvalFile evaluates input as if it were the program in the file at
+// path, loading its imports with loader.
+func testEvalFile(t *testing.T, loader *ModuleLoader, path, input string) object.Object {
+	t.Helper()
+
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors for %q: %v", input, p.Errors())
+	}
+
+	return Eval(program, loader.NewEnvironment(path))
+}
@@ -0,0 +1,110 @@
+package evaluator
+
+import (
+	"github.com/Devashish08/InterPreter-Compiler/object"
+)
+
+// operatorMethods maps infix operators to the instance methods that
+// overload them. `!=` is answered by negating `__eq__`.
+var operatorMethods = map[string]string{
+	"+":  "__add__",
+	"-":  "__sub__",
+	"*":  "__mul__",
+	"/":  "__div__",
+	"==": "__eq__",
+	"!=": "__eq__",
+	"<":  "__lt__",
+	">":  "__gt__",
+}
+
+const (
+	indexMethod = "__index__"
+	lenMethod   = "__len__"
+)
+
+// isOverloaded reports whether left is an instance whose class defines the
+// method for operator.
+func isOverloaded(operator string, left object.Object) bool {
+	instance, ok := left.(*object.Instance)
+	if !ok {
+		return false
+	}
+
+	name, ok := operatorMethods[operator]
+	if !ok {
+		return false
+	}
+
+	method, _ := instance.Class.FindMethod(name)
+	return method != nil
+}
+
+// evalOverloadedInfixExpression calls the method overloading operator on
+// the left operand, passing the right operand as its argument.
+func evalOverloadedInfixExpression(
+	operator string,
+	left, right object.Object,
+) object.Object {
+	result, _ := callMethod(left.(*object.Instance), operatorMethods[operator], right)
+	if IsError(result) {
+		return result
+	}
+
+	if operator == "!=" {
+		return NativeBoolToBooleanObject(!IsTruthy(result))
+	}
+
+	return result
+}
+
+func evalInstanceIndexExpression(instance, index object.Object) object.Object {
+	result, ok := callMethod(instance.(*object.Instance), indexMethod, index)
+	if !ok {
+		return NewError("index operator not supported: %s", instance.Type())
+	}
+
+	return result
+}
+
+func instanceLen(instance *object.Instance) object.Object {
+	result, ok := callMethod(instance, lenMethod)
+	if !ok {
+		return NewError("argument to `len` not supported, got %s", instance.Type())
+	}
+
+	if IsError(result) {
+		return result
+	}
+
+	if result.Type() != object.INTEGER_OBJ {
+		return NewError("%s.%s must return INTEGER, got %s",
+			instance.Class.Name, lenMethod, result.Type())
+	}
+
+	return result
+}
+
+// callMethod calls the named method of instance with args. It reports
+// false when the instance's class does not define the method. A method
+// that takes more parameters than the operator passes is an error.
+func callMethod(
+	instance *object.Instance,
+	name string,
+	args ...object.Object,
+) (object.Object, bool) {
+	method, definedIn := instance.Class.FindMethod(name)
+	if method == nil {
+		return nil, false
+	}
+
+	if len(method.Parameters) > len(args) {
+		noun := "parameters"
+		if len(method.Parameters) == 1 {
+			noun = "parameter"
+		}
+		return NewError("%s.%s takes %d %s, operator passes %d",
+			definedIn.Name, name, len(method.Parameters), noun, len(args)), true
+	}
+
+	return applyFunction(bindMethod(method, definedIn, instance), args), true
+}
@@ -0,0 +1,128 @@
+package evaluator
+
+import (
+	"fmt"
+
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/object"
+	"github.com/Devashish08/InterPreter-Compiler/token"
+)
+
+func quote(node ast.Node, env *object.Environment) object.Object {
+	node, err := evalUnquoteCalls(node, env)
+	if err != nil {
+		return err
+	}
+	return &object.Quote{Node: node}
+}
+
+// evalUnquoteCalls replaces every unquote(...) call inside a quoted node
+// with the AST form of its evaluated argument. The first argument that
+// evaluates to an error, or to a value with no literal form, is returned as
+// an error.
+func evalUnquoteCalls(quoted ast.Node, env *object.Environment) (ast.Node, *object.Error) {
+	var unquoteErr *object.Error
+
+	modified := ast.Modify(quoted, func(node ast.Node) ast.Node {
+		if unquoteErr != nil || !isUnquoteCall(node) {
+			return node
+		}
+
+		call, ok := node.(*ast.CallExpression)
+		if !ok {
+			return node
+		}
+
+		if len(call.Arguments) != 1 {
+			return node
+		}
+
+		unquoted := Eval(call.Arguments[0], env)
+		if err, ok := unquoted.(*object.Error); ok {
+			unquoteErr = err
+			return node
+		}
+
+		converted, ok := convertObjectToASTNode(unquoted, call.Token)
+		if !ok {
+			unquoteErr = withPosition(NewError("cannot unquote %s", typeOf(unquoted)),
+				call.Token).(*object.Error)
+			return node
+		}
+		return converted
+	})
+
+	return modified, unquoteErr
+}
+
+func isUnquoteCall(node ast.Node) bool {
+	callExpression, ok := node.(*ast.CallExpression)
+	if !ok {
+		return false
+	}
+
+	return callExpression.Function.TokenLiteral() == "unquote"
+}
+
+// convertObjectToASTNode turns an evaluated value back into a literal, and
+// reports whether the value has one. Integers, strings, booleans, quotes
+// and arrays of those do. The new node takes its position from the unquote
+// call it replaces.
+func convertObjectToASTNode(obj object.Object, pos token.Token) (ast.Node, bool) {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		t := token.Token{
+			Type:    token.INT,
+			Literal: fmt.Sprintf("%d", obj.Value),
+			Line:    pos.Line,
+			Column:  pos.Column,
+		}
+		return &ast.IntegerLiteral{Token: t, Value: obj.Value}, true
+
+	case *object.String:
+		t := token.Token{
+			Type:    token.STRING,
+			Literal: obj.Value,
+			Line:    pos.Line,
+			Column:  pos.Column,
+		}
+		return &ast.StringLiteral{Token: t, Value: obj.Value}, true
+
+	case *object.Boolean:
+		var t token.Token
+		if obj.Value {
+			t = token.Token{Type: token.TRUE, Literal: "true"}
+		} else {
+			t = token.Token{Type: token.FALSE, Literal: "false"}
+		}
+		t.Line, t.Column = pos.Line, pos.Column
+		return &ast.Boolean{Token: t, Value: obj.Value}, true
+
+	case *object.Array:
+		t := token.Token{
+			Type:    token.LBRACKET,
+			Literal: "[",
+			Line:    pos.Line,
+			Column:  pos.Column,
+		}
+		elements := make([]ast.Expression, len(obj.Elements))
+		for i, element := range obj.Elements {
+			node, ok := convertObjectToASTNode(element, pos)
+			if !ok {
+				return nil, false
+			}
+			expression, ok := node.(ast.Expression)
+			if !ok {
+				return nil, false
+			}
+			elements[i] = expression
+		}
+		return &ast.ArrayLiteral{Token: t, Elements: elements}, true
+
+	case *object.Quote:
+		return obj.Node, obj.Node != nil
+
+	default:
+		return nil, false
+	}
+}
@@ -0,0 +1,44 @@
+package evaluator
+
+import (
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/object"
+)
+
+func evalRangeExpression(
+	node *ast.RangeExpression,
+	env *object.Environment,
+) object.Object {
+	start := Eval(node.Start, env)
+	if IsError(start) {
+		return start
+	}
+
+	end := Eval(node.End, env)
+	if IsError(end) {
+		return end
+	}
+
+	for _, bound := range []object.Object{start, end} {
+		if bound.Type() != object.INTEGER_OBJ {
+			return NewError("range bounds must be INTEGER, got %s", bound.Type())
+		}
+	}
+
+	return &object.Range{
+		Start:     start.(*object.Integer).Value,
+		End:       end.(*object.Integer).Value,
+		Inclusive: node.Inclusive,
+	}
+}
+
+func evalRangeIndexExpression(rng, index object.Object) object.Object {
+	rangeObject := rng.(*object.Range)
+
+	value, ok := rangeObject.Index(index.(*object.Integer).Value)
+	if !ok {
+		return NULL
+	}
+
+	return &object.Integer{Value: value}
+}
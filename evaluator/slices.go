@@ -0,0 +1,132 @@
+package evaluator
+
+import (
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/object"
+)
+
+func evalSliceExpression(
+	node *ast.SliceExpression,
+	env *object.Environment,
+) object.Object {
+	left := Eval(node.Left, env)
+	if IsError(left) {
+		return left
+	}
+
+	bounds := []ast.Expression{node.Start, node.End, node.Step}
+	values := make([]object.Object, len(bounds))
+	for i, bound := range bounds {
+		if bound == nil {
+			continue
+		}
+
+		values[i] = Eval(bound, env)
+		if IsError(values[i]) {
+			return values[i]
+		}
+	}
+
+	switch left := left.(type) {
+	case *object.Array:
+		indices, err := sliceIndices(len(left.Elements), values[0], values[1], values[2])
+		if err != nil {
+			return err
+		}
+
+		elements := make([]object.Object, 0, len(indices))
+		for _, i := range indices {
+			elements = append(elements, left.Elements[i])
+		}
+		return &object.Array{Elements: elements}
+
+	case *object.String:
+		indices, err := sliceIndices(len(left.Value), values[0], values[1], values[2])
+		if err != nil {
+			return err
+		}
+
+		out := make([]byte, 0, len(indices))
+		for _, i := range indices {
+			out = append(out, left.Value[i])
+		}
+		return &object.String{Value: string(out)}
+
+	default:
+		return NewError("slice operator not supported: %s", left.Type())
+	}
+}
+
+// sliceIndices returns the positions selected by [start:end:step] on a
+// sequence of the given length. Omitted bounds are nil. Like Python, bounds
+// past either end are clamped rather than reported, and a negative bound
+// counts from the end.
+func sliceIndices(length int, start, end, step object.Object) ([]int, *object.Error) {
+	stride := int64(1)
+	if step != nil {
+		s, ok := step.(*object.Integer)
+		if !ok {
+			return nil, NewError("slice step must be INTEGER, got %s", step.Type())
+		}
+		if s.Value == 0 {
+			return nil, NewError("slice step cannot be zero")
+		}
+		stride = s.Value
+	}
+
+	n := int64(length)
+
+	// With a negative step the slice walks backwards, so the defaults are
+	// the last element and "before the first element".
+	lower, upper := int64(0), n
+	from, to := int64(0), n
+	if stride < 0 {
+		lower, upper = -1, n-1
+		from, to = n-1, -1
+	}
+
+	for i, bound := range []object.Object{start, end} {
+		if bound == nil {
+			continue
+		}
+
+		b, ok := bound.(*object.Integer)
+		if !ok {
+			return nil, NewError("slice bounds must be INTEGER, got %s", bound.Type())
+		}
+
+		value := b.Value
+		if value < 0 {
+			value += n
+		}
+		if value < lower {
+			value = lower
+		}
+		if value > upper {
+			value = upper
+		}
+
+		if i == 0 {
+			from = value
+		} else {
+			to = value
+		}
+	}
+
+	// The number of steps is worked out up front: adding the stride until
+	// passing the end would overflow for huge strides. The bounds are
+	// clamped to [-1, n], so their difference cannot overflow.
+	count := int64(0)
+	if stride > 0 && from < to {
+		count = (to-from-1)/stride + 1
+	} else if stride < 0 && from > to {
+		count = (to-from+1)/stride + 1
+	}
+
+	indices := make([]int, count)
+	for k := range indices {
+		indices[k] = int(from + int64(k)*stride)
+	}
+
+	return indices, nil
+}
@@ -0,0 +1,179 @@
+package evaluator
+
+import (
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/object"
+)
+
+// TypeErrorKind is the kind of errors raised when a value does not match
+// its type annotation.
+const TypeErrorKind = "TypeError"
+
+// newTypeError reports that the value bound to what, such as "parameter x",
+// does not match the annotation expected.
+func newTypeError(what string, expected *ast.TypeAnnotation, actual object.Object) *object.Error {
+	err := NewError("%s: expected %s, got %s", what, expected.String(), describeType(actual))
+	err.Kind = TypeErrorKind
+	return err
+}
+
+// typeNames are the builtin names that may appear in type annotations.
+var typeNames = map[string]bool{
+	"any": true, "int": true, "string": true, "bool": true, "null": true,
+	"array": true, "hash": true, "range": true, "generator": true, "fn": true,
+}
+
+// checkAnnotation checks the value bound to what against the annotation t.
+// A name in t that is neither a builtin type nor bound in env to a class or
+// an enum is reported as unknown, rather than as a mismatch with obj.
+func checkAnnotation(
+	what string,
+	t *ast.TypeAnnotation,
+	obj object.Object,
+	env *object.Environment,
+) *object.Error {
+	if name := unknownTypeName(t, env); name != "" {
+		err := NewError("%s: unknown type %s", what, name)
+		err.Kind = TypeErrorKind
+		return err
+	}
+	if !checkType(t, obj) {
+		return newTypeError(what, t, obj)
+	}
+	return nil
+}
+
+// unknownTypeName returns the first name in t that does not name a type,
+// or "" if there is none.
+func unknownTypeName(t *ast.TypeAnnotation, env *object.Environment) string {
+	if t.Key != nil {
+		if name := unknownTypeName(t.Key, env); name != "" {
+			return name
+		}
+	}
+	if t.Elem != nil {
+		return unknownTypeName(t.Elem, env)
+	}
+	if typeNames[t.Name] {
+		return ""
+	}
+
+	switch val, _ := env.Get(t.Name); val.(type) {
+	case *object.Class, *object.Enum:
+		return ""
+	}
+	return t.Name
+}
+
+// checkType reports whether obj matches the annotation t. Besides the
+// builtin type names, a name matches instances of the class of that name
+// or its subclasses, and values of the enum of that name.
+func checkType(t *ast.TypeAnnotation, obj object.Object) bool {
+	switch {
+	case t.Key != nil:
+		hash, ok := obj.(*object.Hash)
+		if !ok {
+			return false
+		}
+		for _, pair := range hash.Pairs() {
+			if !checkType(t.Key, pair.Key) || !checkType(t.Elem, pair.Value) {
+				return false
+			}
+		}
+		return true
+
+	case t.Elem != nil:
+		array, ok := obj.(*object.Array)
+		if !ok {
+			return false
+		}
+		for _, elem := range array.Elements {
+			if !checkType(t.Elem, elem) {
+				return false
+			}
+		}
+		return true
+	}
+
+	switch t.Name {
+	case "any":
+		return true
+	case "int":
+		return obj.Type() == object.INTEGER_OBJ
+	case "string":
+		return obj.Type() == object.STRING_OBJ
+	case "bool":
+		return obj.Type() == object.BOOLEAN_OBJ
+	case "null":
+		return obj.Type() == object.NULL_OBJ
+	case "array":
+		return obj.Type() == object.ARRAY_OBJ
+	case "hash":
+		return obj.Type() == object.HASH_OBJ
+	case "range":
+		return obj.Type() == object.RANGE_OBJ
+	case "generator":
+		return obj.Type() == object.GENERATOR_OBJ
+	case "fn":
+		switch obj.(type) {
+		case *object.Function, *object.Builtin, *object.Class, *object.EnumVariant:
+			return true
+		}
+		return false
+	}
+
+	switch obj := obj.(type) {
+	case *object.Instance:
+		for class := obj.Class; class != nil; class = class.Superclass {
+			if class.Name == t.Name {
+				return true
+			}
+		}
+	case *object.EnumValue:
+		return obj.Variant.Enum.Name == t.Name
+	}
+
+	return false
+}
+
+// describeType describes the type of obj in the syntax of annotations, so
+// that errors read like "expected [int], got [string]".
+func describeType(obj object.Object) string {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return "int"
+	case *object.String:
+		return "string"
+	case *object.Boolean:
+		return "bool"
+	case *object.Null:
+		return "null"
+	case *object.Function, *object.Builtin:
+		return "fn"
+	case *object.Range:
+		return "range"
+	case *object.Generator:
+		return "generator"
+	case *object.Instance:
+		return obj.Class.Name
+	case *object.EnumValue:
+		return obj.Variant.Enum.Name
+
+	case *object.Array:
+		if len(obj.Elements) == 0 {
+			return "array"
+		}
+		elem := describeType(obj.Elements[0])
+		for _, e := range obj.Elements[1:] {
+			if describeType(e) != elem {
+				return "array"
+			}
+		}
+		return "[" + elem + "]"
+
+	case *object.Hash:
+		return "hash"
+	}
+
+	return string(obj.Type())
+}
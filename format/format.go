@@ -0,0 +1,344 @@
+/*
+Package format prints programs in the canonical style used by
+`interpreter fmt`.
+
+The style is:
+
+  - four spaces of indentation, one statement per line
+  - single spaces around binary operators and after commas
+  - parentheses only where precedence requires them
+  - at most one blank line between statements, none at the start or end of
+    a block
+  - arrays and hashes on one line when they fit in 80 columns, otherwise
+    one element per line
+  - function literals with a single expression on one line when they fit,
+    otherwise with their body indented below
+
+Comments are kept. A comment on its own line stays on its own line before
+the statement that follows it; a comment after code stays at the end of
+that line. Comments inside expressions that are not broken over several
+lines move to the line after their statement.
+*/
+package format
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/lexer"
+	"github.com/Devashish08/InterPreter-Compiler/parser"
+	"github.com/Devashish08/InterPreter-Compiler/token"
+)
+
+// MaxWidth is the column limit beyond which arrays and hashes are broken
+// over several lines.
+const MaxWidth = 80
+
+const indentation = "    "
+
+// Source formats src. It fails with the parser errors if src does not
+// parse.
+func Source(src []byte) ([]byte, error) {
+	p := parser.New(lexer.New(string(src)))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		return nil, errors.New(strings.Join(p.Errors(), "\n"))
+	}
+
+	pr := newPrinter(string(src))
+	pr.program(program)
+
+	return pr.out.Bytes(), nil
+}
+
+type position struct {
+	line, column int
+}
+
+func positionOf(tok token.Token) position {
+	return position{tok.Line, tok.Column}
+}
+
+func (p position) before(q position) bool {
+	return p.line < q.line || p.line == q.line && p.column < q.column
+}
+
+type printer struct {
+	out *bytes.Buffer
+
+	indent int
+
+	// tokens holds every token of the source except comments, ending with
+	// EOF; index finds a token by its position.
+	tokens []token.Token
+	index  map[position]int
+
+	// comments holds the comments not printed yet, in source order.
+	comments []token.Token
+
+	// lastLine is the source line where the last printed item ended. It
+	// is used to keep blank lines between items.
+	lastLine int
+	// open is set right after an opening brace or bracket, where blank
+	// lines are dropped.
+	open bool
+
+	// flat is set while measuring the single-line form of an expression,
+	// and broken when that form does not exist.
+	flat   bool
+	broken bool
+}
+
+func newPrinter(src string) *printer {
+	p := &printer{out: &bytes.Buffer{}, index: make(map[position]int), open: true}
+
+	l := lexer.New(src)
+	for {
+		tok := l.NextToken()
+		switch tok.Type {
+		case token.COMMENT, token.DOC_COMMENT:
+			p.comments = append(p.comments, tok)
+			continue
+		}
+
+		p.index[positionOf(tok)] = len(p.tokens)
+		p.tokens = append(p.tokens, tok)
+
+		if tok.Type == token.EOF {
+			return p
+		}
+	}
+}
+
+func (p *printer) write(s string) {
+	p.out.WriteString(s)
+}
+
+// column is the width of the current output line.
+func (p *printer) column() int {
+	out := p.out.Bytes()
+	return len(out) - (bytes.LastIndexByte(out, '\n') + 1)
+}
+
+// line starts a new output line for an item that starts on line srcLine
+// of the source, keeping one blank line if the source had any before it.
+func (p *printer) line(srcLine int) {
+	if p.out.Len() > 0 {
+		p.write("\n")
+		if !p.open && srcLine > p.lastLine+1 {
+			p.write("\n")
+		}
+	}
+	p.open = false
+	p.write(strings.Repeat(indentation, p.indent))
+}
+
+// closeLine starts the line of a closing brace or bracket.
+func (p *printer) closeLine() {
+	p.write("\n" + strings.Repeat(indentation, p.indent))
+	p.open = false
+}
+
+// flushComments prints the comments before pos, each on its own line.
+func (p *printer) flushComments(pos position) {
+	if p.flat {
+		return
+	}
+
+	for len(p.comments) > 0 && positionOf(p.comments[0]).before(pos) {
+		comment := p.comments[0]
+		p.comments = p.comments[1:]
+
+		p.line(comment.Line)
+		p.write(comment.Literal)
+		p.lastLine = comment.Line
+	}
+}
+
+// finishItem ends an item whose last token is end. A comment after end on
+// the same line is kept there; comments the item did not place, which come
+// before end, follow on their own lines.
+func (p *printer) finishItem(end token.Token) {
+	p.lastLine = end.Line
+	if p.flat {
+		return
+	}
+
+	endPos := positionOf(end)
+
+	var skipped []token.Token
+	for len(p.comments) > 0 && positionOf(p.comments[0]).before(endPos) {
+		skipped = append(skipped, p.comments[0])
+		p.comments = p.comments[1:]
+	}
+
+	if len(p.comments) > 0 && p.comments[0].Line == end.Line {
+		p.write(" " + p.comments[0].Literal)
+		p.comments = p.comments[1:]
+	}
+
+	for _, comment := range skipped {
+		p.line(comment.Line)
+		p.write(comment.Literal)
+	}
+}
+
+// hasComments reports whether a comment lies between the tokens from and
+// to.
+func (p *printer) hasComments(from, to token.Token) bool {
+	start, end := positionOf(from), positionOf(to)
+	for _, comment := range p.comments {
+		pos := positionOf(comment)
+		if start.before(pos) && pos.before(end) {
+			return true
+		}
+	}
+	return false
+}
+
+// tokenAt returns the index of tok in the source, or -1 when the token was
+// not read from it.
+func (p *printer) tokenAt(tok token.Token) int {
+	i, ok := p.index[positionOf(tok)]
+	if !ok {
+		return -1
+	}
+	return i
+}
+
+// tokenBefore returns the token before tok in the source.
+func (p *printer) tokenBefore(tok token.Token) token.Token {
+	i := p.tokenAt(tok)
+	if i <= 0 {
+		return tok
+	}
+	return p.tokens[i-1]
+}
+
+// closing returns the brace or bracket that closes the one at index open.
+func (p *printer) closing(open int) token.Token {
+	if open < 0 {
+		return p.tokens[len(p.tokens)-1]
+	}
+
+	depth := 0
+	for _, tok := range p.tokens[open:] {
+		switch tok.Type {
+		case token.LBRACE, token.LBRACKET, token.LPAREN:
+			depth++
+		case token.RBRACE, token.RBRACKET, token.RPAREN:
+			depth--
+			if depth == 0 {
+				return tok
+			}
+		}
+	}
+	return p.tokens[len(p.tokens)-1]
+}
+
+// closingOf returns the brace or bracket that closes the one tok is.
+func (p *printer) closingOf(tok token.Token) token.Token {
+	return p.closing(p.tokenAt(tok))
+}
+
+// firstToken returns the first token of node in the source, which for
+// expressions such as `a + b` is not the node's own token.
+func firstToken(node ast.Node) token.Token {
+	var first token.Token
+	found := false
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		if n == nil {
+			return false
+		}
+
+		tok, ok := nodeToken(n)
+		if ok && tok.Line > 0 && (!found || positionOf(tok).before(positionOf(first))) {
+			first, found = tok, true
+		}
+		return true
+	})
+
+	return first
+}
+
+// nodeToken returns the token stored in node, if it has one.
+func nodeToken(node ast.Node) (token.Token, bool) {
+	switch n := node.(type) {
+	case *ast.Program:
+		return token.Token{}, false
+	case *ast.EnumVariant:
+		return n.Name.Token, true
+	case *ast.LetStatement:
+		return n.Token, true
+	case *ast.ReturnStatement:
+		return n.Token, true
+	case *ast.ExpressionStatement:
+		return n.Token, true
+	case *ast.BlockStatement:
+		return n.Token, true
+	case *ast.ClassStatement:
+		return n.Token, true
+	case *ast.EnumStatement:
+		return n.Token, true
+	case *ast.TryStatement:
+		return n.Token, true
+	case *ast.ThrowStatement:
+		return n.Token, true
+	case *ast.DeferStatement:
+		return n.Token, true
+	case *ast.ImportStatement:
+		return n.Token, true
+	case *ast.ExportStatement:
+		return n.Token, true
+	case *ast.YieldStatement:
+		return n.Token, true
+	case *ast.ForStatement:
+		return n.Token, true
+	case *ast.Identifier:
+		return n.Token, true
+	case *ast.Boolean:
+		return n.Token, true
+	case *ast.IntegerLiteral:
+		return n.Token, true
+	case *ast.StringLiteral:
+		return n.Token, true
+	case *ast.PrefixExpression:
+		return n.Token, true
+	case *ast.InfixExpression:
+		return n.Token, true
+	case *ast.IfExpression:
+		return n.Token, true
+	case *ast.FunctionLiteral:
+		return n.Token, true
+	case *ast.MacroLiteral:
+		return n.Token, true
+	case *ast.CallExpression:
+		return n.Token, true
+	case *ast.ArrayLiteral:
+		return n.Token, true
+	case *ast.IndexExpression:
+		return n.Token, true
+	case *ast.SliceExpression:
+		return n.Token, true
+	case *ast.HashLiteral:
+		return n.Token, true
+	case *ast.MemberExpression:
+		return n.Token, true
+	case *ast.AssignExpression:
+		return n.Token, true
+	case *ast.RangeExpression:
+		return n.Token, true
+	case *ast.ComprehensionClause:
+		return n.Token, true
+	case *ast.ArrayComprehension:
+		return n.Token, true
+	case *ast.HashComprehension:
+		return n.Token, true
+	case *ast.TypeAnnotation:
+		return n.Token, true
+	}
+	return token.Token{}, false
+}
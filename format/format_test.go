@@ -0,0 +1,191 @@
+package format
+
+import "testing"
+
+func TestSource(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			"spacing and semicolons",
+			"let x=1+2*3\nputs( x )",
+			"let x = 1 + 2 * 3;\nputs(x);\n",
+		},
+		{
+			"minimal parentheses",
+			"let a = ((1 + 2)) * (3); let b = 1 - (2 - 3); let c = (1 - 2) - 3; -(a + b); (f)(x);",
+			"let a = (1 + 2) * 3;\nlet b = 1 - (2 - 3);\nlet c = 1 - 2 - 3;\n-(a + b);\nf(x);\n",
+		},
+		{
+			"blank lines",
+			"let a = 1;\n\n\n\nlet b = 2;\nlet c = 3;\n",
+			"let a = 1;\n\nlet b = 2;\nlet c = 3;\n",
+		},
+		{
+			"blocks",
+			"if (x) { puts(1) } else {\n\n  puts(2); }\nfor (k, v) in h { puts(k) }",
+			"if (x) {\n    puts(1);\n} else {\n    puts(2);\n}\nfor (k, v) in h {\n    puts(k);\n}\n",
+		},
+		{
+			"if followed by a parenthesised statement",
+			"if (x) { 1 };\n(f)(2)\nif (y) { 3 };\n(4 + 5) * 6",
+			"if (x) {\n    1;\n};\nf(2);\nif (y) {\n    3;\n};\n(4 + 5) * 6;\n",
+		},
+		{
+			"inline function literal",
+			"let add = fn(a: int, b) -> int {\n  a + b;\n};",
+			"let add = fn(a: int, b) -> int { a + b };\n",
+		},
+		{
+			"function literal with statements",
+			"let f = fn(x) { let y = x; y }",
+			"let f = fn(x) {\n    let y = x;\n    y;\n};\n",
+		},
+		{
+			"long array",
+			`let xs = ["aaaaaaaaaaaaaaaaaaaa", "bbbbbbbbbbbbbbbbbbbb", "cccccccccccccccccccc", "dd"];`,
+			"let xs = [\n    \"aaaaaaaaaaaaaaaaaaaa\",\n    \"bbbbbbbbbbbbbbbbbbbb\",\n" +
+				"    \"cccccccccccccccccccc\",\n    \"dd\"\n];\n",
+		},
+		{
+			"short array on several lines",
+			"let xs = [\n1,\n2,\n3\n];",
+			"let xs = [1, 2, 3];\n",
+		},
+		{
+			"long hash in source order",
+			`let h = {"zzzz": fn(x) { x * 2 }, "aaaa": [1, 2, 3], "mmmm": "a fairly long string value"};`,
+			"let h = {\n    \"zzzz\": fn(x) { x * 2 },\n    \"aaaa\": [1, 2, 3],\n" +
+				"    \"mmmm\": \"a fairly long string value\"\n};\n",
+		},
+		{
+			"classes and enums",
+			"class A extends B { init(v) { self.v = v } get() -> int { return self.v; } }\nenum E { X, Y(a, b) }",
+			"class A extends B {\n    init(v) {\n        self.v = v;\n    }\n" +
+				"    get() -> int {\n        return self.v;\n    }\n}\nenum E { X, Y(a, b) }\n",
+		},
+		{
+			"other statements",
+			`import "lib" as lib; export const n: int = 1; try { throw "x" } catch { 1 }` +
+				` let g = fn() { yield 1; defer puts(2) }; let r = 1..=n+1; xs[1:][::2];` +
+				` let ys = [x*2 for x in xs if x>1]; let m = {k:v for (k,v) in h};`,
+			"import \"lib\" as lib;\nexport const n: int = 1;\ntry {\n    throw \"x\";\n} catch {\n    1;\n}\n" +
+				"let g = fn() {\n    yield 1;\n    defer puts(2);\n};\nlet r = 1..=n + 1;\nxs[1:][::2];\n" +
+				"let ys = [x * 2 for x in xs if x > 1];\nlet m = {k: v for (k, v) in h};\n",
+		},
+	}
+
+	for _, tt := range tests {
+		out, err := Source([]byte(tt.input))
+		if err != nil {
+			t.Errorf("%s: Source returned error: %s", tt.name, err)
+			continue
+		}
+		if string(out) != tt.expected {
+			t.Errorf("%s: wrong output.\nwant:\n%s\ngot:\n%s", tt.name, tt.expected, out)
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	input := `// Header.
+
+/// Adds two numbers.
+let add = fn(a, b) { a + b }; // inline
+let xs = [
+  1, // one
+  // before two
+  2
+];
+if (x) {
+  // only a comment
+}
+if (y) {
+  1
+} // after if
+else { 2 }
+// trailing
+`
+	expected := `// Header.
+
+/// Adds two numbers.
+let add = fn(a, b) { a + b }; // inline
+let xs = [
+    1, // one
+    // before two
+    2
+];
+if (x) {
+    // only a comment
+}
+if (y) {
+    1;
+} else {
+    // after if
+    2;
+}
+// trailing
+`
+
+	out, err := Source([]byte(input))
+	if err != nil {
+		t.Fatalf("Source returned error: %s", err)
+	}
+	if string(out) != expected {
+		t.Errorf("wrong output.\nwant:\n%s\ngot:\n%s", expected, out)
+	}
+}
+
+func TestCommentInsideFlatExpression(t *testing.T) {
+	input := "let x = f(1, // first\n  2);\nlet y = 3;"
+	expected := "let x = f(1, 2);\n// first\nlet y = 3;\n"
+
+	out, err := Source([]byte(input))
+	if err != nil {
+		t.Fatalf("Source returned error: %s", err)
+	}
+	if string(out) != expected {
+		t.Errorf("wrong output.\nwant:\n%s\ngot:\n%s", expected, out)
+	}
+}
+
+func TestIdempotent(t *testing.T) {
+	input := `
+// Config.
+let config = {"name": "interpreter", "version": 1, "features": ["macros", "classes", "generators"]};
+
+class Counter {
+    init(start) { self.n = start; } // start
+    next() { self.n += 1; return self.n; }
+}
+
+let fib = fn(n: int) -> int { if (n < 2) { return n; } fib(n - 1) + fib(n - 2) };
+let sign = fn(n) {
+    if (n < 0) { -1 } else { if (n > 0) {
+        1
+    } // positive
+    else { 0 } }
+};
+let nested = [[1, 2], [3, 4], {"k": [x for x in 1..10 if x > 5]}, fn(x) { fn(y) { x + y } }];
+`
+
+	once, err := Source([]byte(input))
+	if err != nil {
+		t.Fatalf("Source returned error: %s", err)
+	}
+	twice, err := Source(once)
+	if err != nil {
+		t.Fatalf("Source returned error on its own output: %s", err)
+	}
+	if string(once) != string(twice) {
+		t.Errorf("formatting is not idempotent.\nfirst:\n%s\nsecond:\n%s", once, twice)
+	}
+}
+
+func TestSourceErrors(t *testing.T) {
+	if _, err := Source([]byte("let = 5;")); err == nil {
+		t.Errorf("expected an error for a program that does not parse")
+	}
+}
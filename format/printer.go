@@ -0,0 +1,520 @@
+package format
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/token"
+)
+
+// Operator precedences, matching the parser's.
+const (
+	precAssign = iota + 2
+	precEquals
+	precLessGreater
+	precRange
+	precSum
+	precProduct
+	precPrefix
+	precCall
+	precPrimary
+)
+
+func (p *printer) program(program *ast.Program) {
+	eof := p.tokens[len(p.tokens)-1]
+
+	p.statements(program.Statements, eof)
+	p.flushComments(positionOf(eof))
+
+	if p.out.Len() > 0 {
+		p.write("\n")
+	}
+}
+
+// statements prints stmts, each on its own line. end is the token that
+// follows the last statement.
+func (p *printer) statements(stmts []ast.Statement, end token.Token) {
+	for i, stmt := range stmts {
+		start, _ := nodeToken(stmt)
+		next := end
+		if i+1 < len(stmts) {
+			next, _ = nodeToken(stmts[i+1])
+		}
+
+		p.flushComments(positionOf(start))
+		p.line(start.Line)
+		p.statement(stmt, next)
+		p.finishItem(p.tokenBefore(next))
+	}
+}
+
+// statement prints stmt. next is the token after it, which decides
+// whether an if expression needs a semicolon to end it.
+func (p *printer) statement(stmt ast.Statement, next token.Token) {
+	switch s := stmt.(type) {
+	case *ast.LetStatement:
+		p.write(s.Token.Literal + " " + s.Name.Value)
+		if s.Type != nil {
+			p.write(": " + s.Type.String())
+		}
+		p.write(" = ")
+		p.expr(s.Value)
+		p.write(";")
+
+	case *ast.ReturnStatement:
+		p.write("return ")
+		p.expr(s.ReturnValue)
+		p.write(";")
+
+	case *ast.ExpressionStatement:
+		p.expr(s.Expression)
+		if _, ok := s.Expression.(*ast.IfExpression); !ok || continuesExpression(next) {
+			p.write(";")
+		}
+
+	case *ast.ClassStatement:
+		p.class(s)
+
+	case *ast.EnumStatement:
+		p.enum(s)
+
+	case *ast.TryStatement:
+		p.write("try ")
+		p.block(s.Block)
+		if s.Catch != nil {
+			p.write(" catch ")
+			if s.CatchParam != nil {
+				p.write("(" + s.CatchParam.Value + ") ")
+			}
+			p.block(s.Catch)
+		}
+		if s.Finally != nil {
+			p.write(" finally ")
+			p.block(s.Finally)
+		}
+
+	case *ast.ThrowStatement:
+		p.write("throw ")
+		p.expr(s.Value)
+		p.write(";")
+
+	case *ast.DeferStatement:
+		p.write("defer ")
+		p.expr(s.Expression)
+		p.write(";")
+
+	case *ast.ImportStatement:
+		p.write("import ")
+		p.expr(s.Path)
+		p.write(" as " + s.Alias.Value + ";")
+
+	case *ast.ExportStatement:
+		p.write("export ")
+		p.statement(s.Statement, next)
+
+	case *ast.YieldStatement:
+		p.write("yield ")
+		p.expr(s.Value)
+		p.write(";")
+
+	case *ast.ForStatement:
+		p.write("for " + loopVariables(s.Key, s.Value) + " in ")
+		p.expr(s.Iterable)
+		p.write(" ")
+		p.block(s.Body)
+	}
+}
+
+// continuesExpression reports whether a statement starting with tok would
+// be read as the continuation of an expression before it, as in `(a)(b)`.
+func continuesExpression(tok token.Token) bool {
+	switch tok.Type {
+	case token.LPAREN, token.LBRACKET, token.MINUS:
+		return true
+	}
+	return false
+}
+
+func loopVariables(key, value *ast.Identifier) string {
+	if key != nil {
+		return "(" + key.Value + ", " + value.Value + ")"
+	}
+	return value.Value
+}
+
+func (p *printer) block(b *ast.BlockStatement) {
+	closing := p.closingOf(b.Token)
+
+	if len(b.Statements) == 0 && !p.hasComments(b.Token, closing) {
+		p.write("{}")
+		return
+	}
+
+	if p.flat {
+		p.broken = true
+		p.write("{ ... }")
+		return
+	}
+
+	p.write("{")
+	// Comments before the brace, such as one between `}` and `else`,
+	// go inside the block.
+	p.indent++
+	p.finishItem(b.Token)
+	p.open = true
+
+	p.statements(b.Statements, closing)
+	p.flushComments(positionOf(closing))
+	p.indent--
+
+	p.closeLine()
+	p.write("}")
+}
+
+func (p *printer) class(s *ast.ClassStatement) {
+	p.write("class " + s.Name.Value)
+
+	last := s.Name.Token
+	if s.Superclass != nil {
+		p.write(" extends " + s.Superclass.Value)
+		last = s.Superclass.Token
+	}
+	p.write(" ")
+
+	open := p.tokenAt(last) + 1
+	lbrace, rbrace := p.tokens[open], p.closing(open)
+
+	if len(s.Methods) == 0 && !p.hasComments(lbrace, rbrace) {
+		p.write("{}")
+		return
+	}
+
+	p.write("{")
+	p.indent++
+	p.finishItem(lbrace)
+	p.open = true
+
+	for _, method := range s.Methods {
+		p.flushComments(positionOf(method.Token))
+		p.line(method.Token.Line)
+		p.signature(method)
+		p.write(" ")
+		p.block(method.Body)
+		p.finishItem(p.closingOf(method.Body.Token))
+	}
+	p.flushComments(positionOf(rbrace))
+	p.indent--
+
+	p.closeLine()
+	p.write("}")
+}
+
+func (p *printer) enum(s *ast.EnumStatement) {
+	p.write("enum " + s.Name.Value + " ")
+
+	open := p.tokenAt(s.Name.Token) + 1
+	p.list(p.tokens[open], "{ ", " }", len(s.Variants),
+		func(i int) token.Token { return s.Variants[i].Name.Token },
+		func(i int) { p.write(s.Variants[i].String()) })
+}
+
+// signature prints a function's name or `fn`, its parameters and its
+// return type.
+func (p *printer) signature(fn *ast.FunctionLiteral) {
+	params := []string{}
+	for i, param := range fn.Parameters {
+		if fn.ParameterTypes != nil && fn.ParameterTypes[i] != nil {
+			params = append(params, param.Value+": "+fn.ParameterTypes[i].String())
+		} else {
+			params = append(params, param.Value)
+		}
+	}
+
+	p.write(fn.Token.Literal + "(" + strings.Join(params, ", ") + ")")
+	if fn.ReturnType != nil {
+		p.write(" -> " + fn.ReturnType.String())
+	}
+}
+
+// body prints the body of a function or macro literal. A body that is a
+// single expression stays on the line when it fits.
+func (p *printer) body(start token.Token, body *ast.BlockStatement) {
+	stmt, ok := singleExpression(body)
+	if ok && !p.hasComments(start, p.closingOf(body.Token)) {
+		inline, ok := p.measure(func() { p.expr(stmt.Expression) })
+		inline = "{ " + inline + " }"
+
+		if ok && (p.flat || p.column()+len(inline) <= MaxWidth) {
+			p.write(inline)
+			return
+		}
+	}
+
+	p.block(body)
+}
+
+// singleExpression returns the statement of a block that holds a single
+// expression other than an if expression.
+func singleExpression(body *ast.BlockStatement) (*ast.ExpressionStatement, bool) {
+	if len(body.Statements) != 1 {
+		return nil, false
+	}
+
+	stmt, ok := body.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		return nil, false
+	}
+	if _, ok := stmt.Expression.(*ast.IfExpression); ok {
+		return nil, false
+	}
+	return stmt, true
+}
+
+// measure renders f on a single line, without printing it, and reports
+// whether that was possible.
+func (p *printer) measure(f func()) (string, bool) {
+	out, flat, broken := p.out, p.flat, p.broken
+	p.out, p.flat, p.broken = &bytes.Buffer{}, true, false
+
+	f()
+	rendered, ok := p.out.String(), !p.broken
+
+	p.out, p.flat, p.broken = out, flat, broken || (flat && !ok)
+	return rendered, ok
+}
+
+// list prints the n items of an array, hash or enum whose opening token is
+// open. They stay on one line, between flatOpen and flatClose, if they fit
+// and contain no comments; otherwise each item gets its own line.
+func (p *printer) list(
+	open token.Token,
+	flatOpen, flatClose string,
+	n int,
+	start func(i int) token.Token,
+	item func(i int),
+) {
+	closing := p.closingOf(open)
+	openStr, closeStr := strings.TrimSpace(flatOpen), strings.TrimSpace(flatClose)
+
+	if n == 0 && !p.hasComments(open, closing) {
+		p.write(openStr + closeStr)
+		return
+	}
+
+	flat, ok := p.measure(func() {
+		p.write(flatOpen)
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				p.write(", ")
+			}
+			item(i)
+		}
+		p.write(flatClose)
+	})
+
+	if p.flat || ok && !p.hasComments(open, closing) && p.column()+len(flat) <= MaxWidth {
+		p.write(flat)
+		return
+	}
+
+	p.write(openStr)
+	p.indent++
+	p.finishItem(open)
+	p.open = true
+
+	for i := 0; i < n; i++ {
+		next := closing
+		if i+1 < n {
+			next = start(i + 1)
+		}
+
+		p.flushComments(positionOf(start(i)))
+		p.line(start(i).Line)
+		item(i)
+		if i+1 < n {
+			p.write(",")
+		}
+		p.finishItem(p.tokenBefore(next))
+	}
+	p.flushComments(positionOf(closing))
+	p.indent--
+
+	p.closeLine()
+	p.write(closeStr)
+}
+
+func precedence(expr ast.Expression) int {
+	switch e := expr.(type) {
+	case *ast.AssignExpression:
+		return precAssign
+	case *ast.InfixExpression:
+		switch e.Operator {
+		case "==", "!=":
+			return precEquals
+		case "<", ">", "in":
+			return precLessGreater
+		case "+", "-":
+			return precSum
+		default:
+			return precProduct
+		}
+	case *ast.RangeExpression:
+		return precRange
+	case *ast.PrefixExpression:
+		return precPrefix
+	case *ast.CallExpression, *ast.IndexExpression, *ast.SliceExpression, *ast.MemberExpression:
+		return precCall
+	}
+	return precPrimary
+}
+
+// operand prints expr, in parentheses when parens is set.
+func (p *printer) operand(expr ast.Expression, parens bool) {
+	if parens {
+		p.write("(")
+		p.expr(expr)
+		p.write(")")
+	} else {
+		p.expr(expr)
+	}
+}
+
+func (p *printer) expr(expr ast.Expression) {
+	switch e := expr.(type) {
+	case *ast.Identifier:
+		p.write(e.Value)
+
+	case *ast.IntegerLiteral:
+		p.write(e.Token.Literal)
+
+	case *ast.Boolean:
+		p.write(e.Token.Literal)
+
+	case *ast.StringLiteral:
+		p.write(`"` + e.Value + `"`)
+
+	case *ast.PrefixExpression:
+		p.write(e.Operator)
+		p.operand(e.Right, precedence(e.Right) < precPrefix)
+
+	case *ast.InfixExpression:
+		prec := precedence(e)
+		p.operand(e.Left, precedence(e.Left) < prec)
+		p.write(" " + e.Operator + " ")
+		p.operand(e.Right, precedence(e.Right) <= prec)
+
+	case *ast.RangeExpression:
+		p.operand(e.Start, precedence(e.Start) < precRange)
+		p.write(e.Token.Literal)
+		p.operand(e.End, precedence(e.End) <= precRange)
+
+	case *ast.AssignExpression:
+		p.expr(e.Target)
+		p.write(" " + e.Operator + " ")
+		p.expr(e.Value)
+
+	case *ast.IfExpression:
+		if p.flat {
+			p.broken = true
+		}
+		p.write("if (")
+		p.expr(e.Condition)
+		p.write(") ")
+		p.block(e.Consequence)
+		if e.Alternative != nil {
+			p.write(" else ")
+			p.block(e.Alternative)
+		}
+
+	case *ast.FunctionLiteral:
+		p.signature(e)
+		p.write(" ")
+		p.body(e.Token, e.Body)
+
+	case *ast.MacroLiteral:
+		params := []string{}
+		for _, param := range e.Parameters {
+			params = append(params, param.Value)
+		}
+		p.write("macro(" + strings.Join(params, ", ") + ") ")
+		p.body(e.Token, e.Body)
+
+	case *ast.CallExpression:
+		p.operand(e.Function, precedence(e.Function) < precCall)
+		p.write("(")
+		for i, arg := range e.Arguments {
+			if i > 0 {
+				p.write(", ")
+			}
+			p.expr(arg)
+		}
+		p.write(")")
+
+	case *ast.IndexExpression:
+		p.operand(e.Left, precedence(e.Left) < precCall)
+		p.write("[")
+		p.expr(e.Index)
+		p.write("]")
+
+	case *ast.SliceExpression:
+		p.operand(e.Left, precedence(e.Left) < precCall)
+		p.write("[")
+		if e.Start != nil {
+			p.expr(e.Start)
+		}
+		p.write(":")
+		if e.End != nil {
+			p.expr(e.End)
+		}
+		if e.Step != nil {
+			p.write(":")
+			p.expr(e.Step)
+		}
+		p.write("]")
+
+	case *ast.MemberExpression:
+		p.operand(e.Object, precedence(e.Object) < precCall)
+		p.write("." + e.Property.Value)
+
+	case *ast.ArrayLiteral:
+		p.list(e.Token, "[", "]", len(e.Elements),
+			func(i int) token.Token { return firstToken(e.Elements[i]) },
+			func(i int) { p.expr(e.Elements[i]) })
+
+	case *ast.HashLiteral:
+		p.list(e.Token, "{", "}", len(e.Pairs),
+			func(i int) token.Token { return firstToken(e.Pairs[i].Key) },
+			func(i int) {
+				p.expr(e.Pairs[i].Key)
+				p.write(": ")
+				p.expr(e.Pairs[i].Value)
+			})
+
+	case *ast.ArrayComprehension:
+		p.write("[")
+		p.expr(e.Element)
+		p.clause(e.Clause)
+		p.write("]")
+
+	case *ast.HashComprehension:
+		p.write("{")
+		p.expr(e.Key)
+		p.write(": ")
+		p.expr(e.Value)
+		p.clause(e.Clause)
+		p.write("}")
+
+	default:
+		p.write(expr.String())
+	}
+}
+
+func (p *printer) clause(c *ast.ComprehensionClause) {
+	p.write(" for " + loopVariables(c.Key, c.Value) + " in ")
+	p.expr(c.Iterable)
+	if c.Condition != nil {
+		p.write(" if ")
+		p.expr(c.Condition)
+	}
+}
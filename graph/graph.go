@@ -0,0 +1,270 @@
+/*
+Package graph renders programs and runtime environments as Graphviz DOT
+graphs, for `interpreter graph`.
+
+  - AST: The syntax tree of a program, one node per ast.Node
+  - Environment: The chain of environments reachable from a global
+    environment, with the closures that capture them
+*/
+package graph
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/object"
+)
+
+// maxValueWidth limits how much of a bound value's Inspect output an
+// environment node shows.
+const maxValueWidth = 40
+
+// AST writes the syntax tree of program as a DOT digraph. Each node is
+// labeled with its type and, except for the program itself, its token
+// literal; edges go from a node to its children in source order.
+func AST(w io.Writer, program *ast.Program) error {
+	var out strings.Builder
+
+	out.WriteString("digraph ast {\n")
+	out.WriteString("\tordering=out;\n")
+	out.WriteString("\tnode [shape=box, fontname=\"monospace\"];\n")
+
+	ast.Walk(&astGraph{out: &out}, program)
+
+	out.WriteString("}\n")
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// astGraph is the ast.Visitor that writes the nodes and edges. parents
+// holds the ids of the nodes whose children are being visited.
+type astGraph struct {
+	out     *strings.Builder
+	next    int
+	parents []string
+}
+
+func (g *astGraph) Visit(node ast.Node) ast.Visitor {
+	if node == nil {
+		g.parents = g.parents[:len(g.parents)-1]
+		return nil
+	}
+
+	id := fmt.Sprintf("n%d", g.next)
+	g.next++
+
+	fmt.Fprintf(g.out, "\t%s [label=%s];\n", id, quote(astLabel(node)))
+	if len(g.parents) > 0 {
+		fmt.Fprintf(g.out, "\t%s -> %s;\n", g.parents[len(g.parents)-1], id)
+	}
+
+	g.parents = append(g.parents, id)
+	return g
+}
+
+func astLabel(node ast.Node) string {
+	kind := strings.TrimPrefix(fmt.Sprintf("%T", node), "*ast.")
+	if _, ok := node.(*ast.Program); ok {
+		return kind
+	}
+	return kind + "\n" + node.TokenLiteral()
+}
+
+// Environment writes the environments reachable from env as a DOT digraph.
+// Each environment lists its bindings. Edges lead from an environment to
+// the one enclosing it, from a binding to the function or module it holds,
+// and from a function to the environment it captured.
+func Environment(w io.Writer, env *object.Environment) error {
+	var out strings.Builder
+
+	out.WriteString("digraph environment {\n")
+	out.WriteString("\tnode [fontname=\"monospace\"];\n")
+
+	g := &envGraph{
+		out:       &out,
+		global:    env,
+		envs:      make(map[*object.Environment]string),
+		functions: make(map[*object.Function]string),
+		classes:   make(map[*object.Class]string),
+	}
+	g.environment(env)
+
+	out.WriteString("}\n")
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// envGraph writes each environment, function and class once, naming them
+// by the order in which they are reached.
+type envGraph struct {
+	out       *strings.Builder
+	global    *object.Environment
+	envs      map[*object.Environment]string
+	functions map[*object.Function]string
+	classes   map[*object.Class]string
+}
+
+// environment writes env and everything reachable from it, and returns
+// its node id.
+func (g *envGraph) environment(env *object.Environment) string {
+	if id, ok := g.envs[env]; ok {
+		return id
+	}
+
+	id := fmt.Sprintf("env%d", len(g.envs))
+	g.envs[env] = id
+
+	lines := []string{g.envTitle(env)}
+	for _, name := range env.Names() {
+		value, _ := env.Get(name)
+		lines = append(lines, name+" = "+describe(value))
+	}
+	fmt.Fprintf(g.out, "\t%s [shape=box, label=%s];\n", id, quoteLines(lines))
+
+	if outer := env.Outer(); outer != nil {
+		fmt.Fprintf(g.out, "\t%s -> %s [label=\"outer\", style=dashed];\n", id, g.environment(outer))
+	}
+
+	for _, name := range env.Names() {
+		value, _ := env.Get(name)
+		if target := g.value(value); target != "" {
+			fmt.Fprintf(g.out, "\t%s -> %s [label=%s];\n", id, target, quote(name))
+		}
+	}
+
+	return id
+}
+
+func (g *envGraph) envTitle(env *object.Environment) string {
+	switch {
+	case env == g.global:
+		return "global"
+	case env.IsTopLevel():
+		if module := env.Module(); module != nil {
+			return "module " + module.Name
+		}
+		return "top level"
+	case env.Frame() != nil && env.Frame() != env.Outer().Frame():
+		return "call"
+	default:
+		return "scope"
+	}
+}
+
+// value writes the node for a bound value that refers to environments,
+// such as a closure, and returns its id. It returns "" for other values.
+func (g *envGraph) value(value object.Object) string {
+	switch v := value.(type) {
+	case *object.Function:
+		return g.function(v)
+
+	case *object.Module:
+		if v.Env == nil {
+			return ""
+		}
+		return g.environment(v.Env)
+
+	case *object.Class:
+		return g.class(v)
+	}
+
+	return ""
+}
+
+// class writes class with its methods and superclass, and returns the id
+// of its node.
+func (g *envGraph) class(class *object.Class) string {
+	if id, ok := g.classes[class]; ok {
+		return id
+	}
+
+	id := fmt.Sprintf("class%d", len(g.classes))
+	g.classes[class] = id
+
+	fmt.Fprintf(g.out, "\t%s [shape=ellipse, label=%s];\n", id, quote("class "+class.Name))
+	if class.Superclass != nil {
+		fmt.Fprintf(g.out, "\t%s -> %s [label=\"extends\"];\n", id, g.class(class.Superclass))
+	}
+	for _, name := range sortedMethods(class) {
+		fmt.Fprintf(g.out, "\t%s -> %s [label=%s];\n", id, g.function(class.Methods[name]), quote(name))
+	}
+
+	return id
+}
+
+// function writes fn and the environment it captured, and returns the id
+// of fn's node.
+func (g *envGraph) function(fn *object.Function) string {
+	if id, ok := g.functions[fn]; ok {
+		return id
+	}
+
+	id := fmt.Sprintf("fn%d", len(g.functions))
+	g.functions[fn] = id
+
+	fmt.Fprintf(g.out, "\t%s [shape=ellipse, label=%s];\n", id, quote(signature(fn)))
+	if fn.Env != nil {
+		fmt.Fprintf(g.out, "\t%s -> %s [label=\"captures\"];\n", id, g.environment(fn.Env))
+	}
+
+	return id
+}
+
+func sortedMethods(class *object.Class) []string {
+	names := make([]string, 0, len(class.Methods))
+	for name := range class.Methods {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// signature renders a function without its body.
+func signature(fn *object.Function) string {
+	params := []string{}
+	for _, p := range fn.Parameters {
+		params = append(params, p.Value)
+	}
+	return "fn(" + strings.Join(params, ", ") + ")"
+}
+
+// describe renders a bound value on one short line.
+func describe(value object.Object) string {
+	if fn, ok := value.(*object.Function); ok {
+		return signature(fn)
+	}
+
+	s := strings.Join(strings.Fields(value.Inspect()), " ")
+	if len(s) > maxValueWidth {
+		s = s[:maxValueWidth-3] + "..."
+	}
+	return s
+}
+
+// quote renders s as a DOT string of centered lines.
+func quote(s string) string {
+	return `"` + strings.ReplaceAll(escape(s), "\n", `\n`) + `"`
+}
+
+// quoteLines renders lines as a DOT string of left-aligned lines.
+func quoteLines(lines []string) string {
+	var out strings.Builder
+
+	out.WriteByte('"')
+	for _, line := range lines {
+		out.WriteString(escape(line) + `\l`)
+	}
+	out.WriteByte('"')
+
+	return out.String()
+}
+
+func escape(s string) string {
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	return strings.ReplaceAll(s, `"`, `\"`)
+}
@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/evaluator"
+	"github.com/Devashish08/InterPreter-Compiler/lexer"
+	"github.com/Devashish08/InterPreter-Compiler/object"
+	"github.com/Devashish08/InterPreter-Compiler/parser"
+)
+
+func TestAST(t *testing.T) {
+	expected := `digraph ast {
+	ordering=out;
+	node [shape=box, fontname="monospace"];
+	n0 [label="Program"];
+	n1 [label="LetStatement\nlet"];
+	n0 -> n1;
+	n2 [label="Identifier\nx"];
+	n1 -> n2;
+	n3 [label="InfixExpression\n+"];
+	n1 -> n3;
+	n4 [label="IntegerLiteral\n1"];
+	n3 -> n4;
+	n5 [label="StringLiteral\na\\b"];
+	n3 -> n5;
+}
+`
+
+	var out bytes.Buffer
+	if err := AST(&out, parse(t, `let x = 1 + "a\b";`)); err != nil {
+		t.Fatalf("AST returned error: %s", err)
+	}
+
+	if out.String() != expected {
+		t.Errorf("wrong output.\nwant:\n%s\ngot:\n%s", expected, out.String())
+	}
+}
+
+func TestEnvironment(t *testing.T) {
+	input := `
+let newAdder = fn(x) { fn(y) { x + y } };
+let addTwo = newAdder(2);
+class Point { init(x) { self.x = x; } }
+let name = "origin";
+`
+	env := object.NewEnvironment()
+	if result, ok := evaluator.Eval(parse(t, input), env).(*object.Error); ok {
+		t.Fatalf("evaluation failed: %s", result.Inspect())
+	}
+
+	var out bytes.Buffer
+	if err := Environment(&out, env); err != nil {
+		t.Fatalf("Environment returned error: %s", err)
+	}
+
+	expected := []string{
+		`env0 [shape=box, label="global\lPoint = class Point\laddTwo = fn(y)\lname = origin\lnewAdder = fn(x)\l"];`,
+		`class0 [shape=ellipse, label="class Point"];`,
+		`class0 -> fn0 [label="init"];`,
+		`fn0 -> env0 [label="captures"];`,
+		`env0 -> class0 [label="Point"];`,
+		`fn1 [shape=ellipse, label="fn(y)"];`,
+		`env1 [shape=box, label="call\lx = 2\l"];`,
+		`env1 -> env0 [label="outer", style=dashed];`,
+		`fn1 -> env1 [label="captures"];`,
+		`env0 -> fn1 [label="addTwo"];`,
+		`env0 -> fn2 [label="newAdder"];`,
+	}
+
+	for _, line := range expected {
+		if !strings.Contains(out.String(), "\t"+line+"\n") {
+			t.Errorf("output is missing %q. got:\n%s", line, out.String())
+		}
+	}
+}
+
+func parse(t *testing.T, input string) *ast.Program {
+	t.Helper()
+
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors: %v", p.Errors())
+	}
+	return program
+}
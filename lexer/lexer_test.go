@@ -24,6 +24,10 @@ return true;
 
     10 == 10;
     10 != 9;
+class Counter extends Base { inc() { self.n += 1; } }
+x -= 1; x *= 2; x /= 2;
+for i in 1..5 { 1..=5 }
+fn(x: int) -> bool
 `
 
 	tests := []struct {
@@ -103,6 +107,54 @@ return true;
 		{token.NOT_EQ, "!="},
 		{token.INT, "9"},
 		{token.SEMICOLON, ";"},
+		{token.CLASS, "class"},
+		{token.IDENT, "Counter"},
+		{token.EXTENDS, "extends"},
+		{token.IDENT, "Base"},
+		{token.LBRACE, "{"},
+		{token.IDENT, "inc"},
+		{token.LPAREN, "("},
+		{token.RPAREN, ")"},
+		{token.LBRACE, "{"},
+		{token.IDENT, "self"},
+		{token.DOT, "."},
+		{token.IDENT, "n"},
+		{token.PLUS_ASSIGN, "+="},
+		{token.INT, "1"},
+		{token.SEMICOLON, ";"},
+		{token.RBRACE, "}"},
+		{token.RBRACE, "}"},
+		{token.IDENT, "x"},
+		{token.MINUS_ASSIGN, "-="},
+		{token.INT, "1"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "x"},
+		{token.ASTERISK_ASSIGN, "*="},
+		{token.INT, "2"},
+		{token.SEMICOLON, ";"},
+		{token.IDENT, "x"},
+		{token.SLASH_ASSIGN, "/="},
+		{token.INT, "2"},
+		{token.SEMICOLON, ";"},
+		{token.FOR, "for"},
+		{token.IDENT, "i"},
+		{token.IN, "in"},
+		{token.INT, "1"},
+		{token.DOTDOT, ".."},
+		{token.INT, "5"},
+		{token.LBRACE, "{"},
+		{token.INT, "1"},
+		{token.DOTDOT_EQ, "..="},
+		{token.INT, "5"},
+		{token.RBRACE, "}"},
+		{token.FUNCTION, "fn"},
+		{token.LPAREN, "("},
+		{token.IDENT, "x"},
+		{token.COLON, ":"},
+		{token.IDENT, "int"},
+		{token.RPAREN, ")"},
+		{token.ARROW, "->"},
+		{token.IDENT, "bool"},
 		{token.EOF, ""},
 	}
 	l := New(input)
@@ -198,3 +250,82 @@ return true;
 // 		}
 // 	}
 // }
+
+func TestTokenPositions(t *testing.T) {
+	input := `let x = 5;
+  x += "ab";
+}`
+
+	tests := []struct {
+		expectedType   token.TokenType
+		expectedLine   int
+		expectedColumn int
+	}{
+		{token.LET, 1, 1},
+		{token.IDENT, 1, 5},
+		{token.ASSIGN, 1, 7},
+		{token.INT, 1, 9},
+		{token.SEMICOLON, 1, 10},
+		{token.IDENT, 2, 3},
+		{token.PLUS_ASSIGN, 2, 5},
+		{token.STRING, 2, 8},
+		{token.SEMICOLON, 2, 12},
+		{token.RBRACE, 3, 1},
+		{token.EOF, 3, 2},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Line != tt.expectedLine || tok.Column != tt.expectedColumn {
+			t.Fatalf("tests[%d] - position wrong. expected=%d:%d, got=%d:%d",
+				i, tt.expectedLine, tt.expectedColumn, tok.Line, tok.Column)
+		}
+	}
+}
+
+func TestComments(t *testing.T) {
+	input := `// a comment
+/// Adds.
+let x = 5; // trailing
+x / 2;`
+
+	tests := []struct {
+		expectedType    token.TokenType
+		expectedLiteral string
+	}{
+		{token.COMMENT, "// a comment"},
+		{token.DOC_COMMENT, "/// Adds."},
+		{token.LET, "let"},
+		{token.IDENT, "x"},
+		{token.ASSIGN, "="},
+		{token.INT, "5"},
+		{token.SEMICOLON, ";"},
+		{token.COMMENT, "// trailing"},
+		{token.IDENT, "x"},
+		{token.SLASH, "/"},
+		{token.INT, "2"},
+		{token.SEMICOLON, ";"},
+		{token.EOF, ""},
+	}
+
+	l := New(input)
+
+	for i, tt := range tests {
+		tok := l.NextToken()
+
+		if tok.Type != tt.expectedType {
+			t.Fatalf("tests[%d] - tokentype wrong. expected=%q, got=%q", i, tt.expectedType, tok.Type)
+		}
+
+		if tok.Literal != tt.expectedLiteral {
+			t.Fatalf("tests[%d] - literal wrong. expected=%q, got=%q", i, tt.expectedLiteral, tok.Literal)
+		}
+	}
+}
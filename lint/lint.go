@@ -0,0 +1,325 @@
+/*
+Package lint finds suspicious code that runs but probably does not do what
+was meant, for `interpreter lint`.
+
+Each check is a rule with a stable ID, which selects or disables it and is
+printed with every issue it finds:
+
+  - unused-let: a let or const binding that is never used
+  - unused-parameter: a function parameter that is never used
+  - unreachable-code: a statement after a return or throw in the same block
+  - self-comparison: a comparison of an expression with itself
+  - builtin-arity: a call to a builtin with a number of arguments it rejects
+
+Names are resolved by the resolver package, so a local binding that shadows
+a builtin is not mistaken for it. Bindings and parameters whose name starts
+with an underscore, and bindings that are exported, count as used.
+*/
+package lint
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/evaluator"
+	"github.com/Devashish08/InterPreter-Compiler/resolver"
+	"github.com/Devashish08/InterPreter-Compiler/token"
+)
+
+// Rule IDs.
+const (
+	UnusedLet       = "unused-let"
+	UnusedParameter = "unused-parameter"
+	UnreachableCode = "unreachable-code"
+	SelfComparison  = "self-comparison"
+	BuiltinArity    = "builtin-arity"
+)
+
+// Rule describes one check.
+type Rule struct {
+	ID          string
+	Description string
+}
+
+// Rules lists every rule Lint knows.
+var Rules = []Rule{
+	{UnusedLet, "let or const binding that is never used"},
+	{UnusedParameter, "function parameter that is never used"},
+	{UnreachableCode, "statement after a return or throw in the same block"},
+	{SelfComparison, "comparison of an expression with itself"},
+	{BuiltinArity, "builtin called with a number of arguments it rejects"},
+}
+
+// IsRule reports whether id is the ID of a rule.
+func IsRule(id string) bool {
+	for _, rule := range Rules {
+		if rule.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
+// Config selects the rules Lint runs.
+type Config struct {
+	// Disabled holds the IDs of the rules not to run.
+	Disabled map[string]bool
+}
+
+// Issue is a problem found by a rule.
+type Issue struct {
+	File    string `json:"file,omitempty"`
+	Line    int    `json:"line"`
+	Column  int    `json:"column"`
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (i Issue) String() string {
+	position := fmt.Sprintf("%d:%d", i.Line, i.Column)
+	if i.File != "" {
+		position = i.File + ":" + position
+	}
+	return fmt.Sprintf("%s: %s (%s)", position, i.Message, i.Rule)
+}
+
+// Lint runs the rules config enables over program and returns the issues
+// they find, ordered by position.
+func Lint(program *ast.Program, config Config) []Issue {
+	l := &linter{
+		program:  program,
+		config:   config,
+		resolved: resolver.Resolve(program),
+		issues:   []Issue{},
+	}
+
+	l.unused()
+	ast.Inspect(program, l.inspect)
+
+	sort.SliceStable(l.issues, func(i, j int) bool {
+		a, b := l.issues[i], l.issues[j]
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+
+	return l.issues
+}
+
+type linter struct {
+	program  *ast.Program
+	config   Config
+	resolved *resolver.Result
+	issues   []Issue
+}
+
+func (l *linter) report(rule string, tok token.Token, format string, args ...interface{}) {
+	if l.config.Disabled[rule] {
+		return
+	}
+
+	l.issues = append(l.issues, Issue{
+		Line:    tok.Line,
+		Column:  tok.Column,
+		Rule:    rule,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// unused reports the bindings and parameters that are never referenced.
+func (l *linter) unused() {
+	exported := make(map[*ast.Identifier]bool)
+	for _, stmt := range l.program.Statements {
+		if export, ok := stmt.(*ast.ExportStatement); ok {
+			if let, ok := export.Statement.(*ast.LetStatement); ok {
+				exported[let.Name] = true
+			}
+		}
+	}
+
+	for _, symbol := range l.resolved.Symbols {
+		if len(symbol.References) > 0 || strings.HasPrefix(symbol.Name, "_") {
+			continue
+		}
+
+		switch symbol.Kind {
+		case resolver.Variable:
+			if !exported[symbol.Decl] {
+				l.report(UnusedLet, symbol.Decl.Token, "%s is declared but never used", symbol.Name)
+			}
+		case resolver.Parameter:
+			l.report(UnusedParameter, symbol.Decl.Token, "parameter %s is never used", symbol.Name)
+		}
+	}
+}
+
+func (l *linter) inspect(node ast.Node) bool {
+	switch n := node.(type) {
+	case *ast.Program:
+		l.unreachable(n.Statements)
+
+	case *ast.BlockStatement:
+		l.unreachable(n.Statements)
+
+	case *ast.InfixExpression:
+		l.selfComparison(n)
+
+	case *ast.CallExpression:
+		l.builtinArity(n)
+	}
+	return true
+}
+
+// unreachable reports the first statement after a return or throw.
+func (l *linter) unreachable(stmts []ast.Statement) {
+	for i, stmt := range stmts {
+		var keyword string
+		switch stmt.(type) {
+		case *ast.ReturnStatement:
+			keyword = "return"
+		case *ast.ThrowStatement:
+			keyword = "throw"
+		default:
+			continue
+		}
+
+		if i+1 < len(stmts) && stmts[i+1] != nil {
+			l.report(UnreachableCode, statementToken(stmts[i+1]), "unreachable code after %s", keyword)
+		}
+		return
+	}
+}
+
+func (l *linter) selfComparison(n *ast.InfixExpression) {
+	switch n.Operator {
+	case "==", "!=", "<", ">":
+	default:
+		return
+	}
+
+	if pure(n.Left) && pure(n.Right) && n.Left.String() == n.Right.String() {
+		l.report(SelfComparison, n.Token, "both sides of %s are the same expression", n.Operator)
+	}
+}
+
+// pure reports whether evaluating expr twice gives the same value, which
+// holds when it calls nothing.
+func pure(expr ast.Expression) bool {
+	switch e := expr.(type) {
+	case *ast.Identifier, *ast.IntegerLiteral, *ast.StringLiteral, *ast.Boolean:
+		return true
+	case *ast.PrefixExpression:
+		return pure(e.Right)
+	case *ast.InfixExpression:
+		return pure(e.Left) && pure(e.Right)
+	case *ast.MemberExpression:
+		return pure(e.Object)
+	case *ast.IndexExpression:
+		return pure(e.Left) && pure(e.Index)
+	}
+	return false
+}
+
+func (l *linter) builtinArity(call *ast.CallExpression) {
+	ident, ok := call.Function.(*ast.Identifier)
+	if !ok {
+		return
+	}
+
+	symbol, ok := l.resolved.References[ident]
+	if !ok || symbol.Kind != resolver.Builtin {
+		return
+	}
+
+	builtin := evaluator.GetBuiltins()[symbol.Name]
+	if builtin.AcceptsArgs(len(call.Arguments)) {
+		return
+	}
+
+	l.report(BuiltinArity, ident.Token, "%s takes %s, got %d",
+		ident.Value, arity(builtin.MinArgs, builtin.MaxArgs), len(call.Arguments))
+}
+
+// arity describes an accepted number of arguments, such as "1 argument"
+// or "1 or 2 arguments".
+func arity(min, max int) string {
+	switch {
+	case max < 0:
+		return fmt.Sprintf("at least %d %s", min, plural(min))
+	case min == max:
+		return fmt.Sprintf("%d %s", min, plural(min))
+	case max == min+1:
+		return fmt.Sprintf("%d or %d arguments", min, max)
+	default:
+		return fmt.Sprintf("%d to %d arguments", min, max)
+	}
+}
+
+func plural(n int) string {
+	if n == 1 {
+		return "argument"
+	}
+	return "arguments"
+}
+
+// statementToken returns the first token of stmt.
+func statementToken(stmt ast.Statement) token.Token {
+	switch s := stmt.(type) {
+	case *ast.LetStatement:
+		return s.Token
+	case *ast.ReturnStatement:
+		return s.Token
+	case *ast.ExpressionStatement:
+		return s.Token
+	case *ast.BlockStatement:
+		return s.Token
+	case *ast.ClassStatement:
+		return s.Token
+	case *ast.EnumStatement:
+		return s.Token
+	case *ast.TryStatement:
+		return s.Token
+	case *ast.ThrowStatement:
+		return s.Token
+	case *ast.DeferStatement:
+		return s.Token
+	case *ast.ImportStatement:
+		return s.Token
+	case *ast.ExportStatement:
+		return s.Token
+	case *ast.YieldStatement:
+		return s.Token
+	case *ast.ForStatement:
+		return s.Token
+	}
+	return token.Token{}
+}
+
+// WriteText writes one issue per line.
+func WriteText(w io.Writer, issues []Issue) error {
+	var out strings.Builder
+	for _, issue := range issues {
+		out.WriteString(issue.String() + "\n")
+	}
+
+	_, err := io.WriteString(w, out.String())
+	return err
+}
+
+// WriteJSON writes issues as a JSON array of objects with the fields file,
+// line, column, rule and message.
+func WriteJSON(w io.Writer, issues []Issue) error {
+	if issues == nil {
+		issues = []Issue{}
+	}
+
+	data, err := json.MarshalIndent(issues, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(append(data, '\n'))
+	return err
+}
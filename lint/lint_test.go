@@ -0,0 +1,147 @@
+package lint
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/lexer"
+	"github.com/Devashish08/InterPreter-Compiler/parser"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []Issue
+	}{
+		{
+			"let a = 1; let b = 2; puts(b);",
+			[]Issue{{Line: 1, Column: 5, Rule: UnusedLet, Message: "a is declared but never used"}},
+		},
+		{
+			"let f = fn(x, y, _z) { x }; f(1, 2, 3);",
+			[]Issue{{Line: 1, Column: 15, Rule: UnusedParameter, Message: "parameter y is never used"}},
+		},
+		{
+			"let f = fn() {\n  return 1;\n  puts(2);\n  puts(3);\n}; f();",
+			[]Issue{{Line: 3, Column: 3, Rule: UnreachableCode, Message: "unreachable code after return"}},
+		},
+		{
+			"let f = fn() { throw \"x\"; 1 }; f();",
+			[]Issue{{Line: 1, Column: 27, Rule: UnreachableCode, Message: "unreachable code after throw"}},
+		},
+		{
+			"let h = {\"a\": 1}; if (h.a == h.a) { 1 }; if (h[\"a\"] != h[\"a\"]) { 2 };",
+			[]Issue{
+				{Line: 1, Column: 27, Rule: SelfComparison, Message: "both sides of == are the same expression"},
+				{Line: 1, Column: 53, Rule: SelfComparison, Message: "both sides of != are the same expression"},
+			},
+		},
+		{
+			"let f = fn() { 1 }; f() == f();",
+			[]Issue{},
+		},
+		{
+			"len(1, 2); push([1]); error(); puts(); puts(1, 2, 3);",
+			[]Issue{
+				{Line: 1, Column: 1, Rule: BuiltinArity, Message: "len takes 1 argument, got 2"},
+				{Line: 1, Column: 12, Rule: BuiltinArity, Message: "push takes 2 arguments, got 1"},
+				{Line: 1, Column: 23, Rule: BuiltinArity, Message: "error takes 1 or 2 arguments, got 0"},
+			},
+		},
+		{
+			"let first = fn(a, b) { a + b }; first(1, 2);",
+			[]Issue{},
+		},
+		{
+			"export let api = 1; let _private = 2; let unused = macro(x) { x };",
+			[]Issue{{Line: 1, Column: 43, Rule: UnusedLet, Message: "unused is declared but never used"}},
+		},
+	}
+
+	for _, tt := range tests {
+		issues := Lint(parse(t, tt.input), Config{})
+
+		if len(issues) != len(tt.expected) {
+			t.Errorf("wrong issues for %q.\nwant=%v\ngot= %v", tt.input, tt.expected, issues)
+			continue
+		}
+		for i, issue := range issues {
+			if issue != tt.expected[i] {
+				t.Errorf("wrong issue for %q. want=%v, got=%v", tt.input, tt.expected[i], issue)
+			}
+		}
+	}
+}
+
+func TestLintDisabledRules(t *testing.T) {
+	program := parse(t, "let a = 1; let f = fn(x) { return 1; x }; f(1);")
+
+	config := Config{Disabled: map[string]bool{UnusedLet: true, UnreachableCode: true}}
+	issues := Lint(program, config)
+
+	if len(issues) != 0 {
+		t.Errorf("disabled rules reported issues: %v", issues)
+	}
+
+	if len(Lint(program, Config{})) != 2 {
+		t.Errorf("expected 2 issues with every rule enabled")
+	}
+}
+
+func TestWriteText(t *testing.T) {
+	issues := []Issue{{File: "main.monkey", Line: 3, Column: 5, Rule: UnusedLet, Message: "a is declared but never used"}}
+
+	var out bytes.Buffer
+	if err := WriteText(&out, issues); err != nil {
+		t.Fatalf("WriteText returned error: %s", err)
+	}
+
+	expected := "main.monkey:3:5: a is declared but never used (unused-let)\n"
+	if out.String() != expected {
+		t.Errorf("wrong output. want=%q, got=%q", expected, out.String())
+	}
+}
+
+func TestWriteJSON(t *testing.T) {
+	tests := []struct {
+		issues   []Issue
+		expected string
+	}{
+		{nil, "[]\n"},
+		{
+			[]Issue{{File: "a.monkey", Line: 1, Column: 2, Rule: SelfComparison, Message: "both sides of == are the same expression"}},
+			`[
+  {
+    "file": "a.monkey",
+    "line": 1,
+    "column": 2,
+    "rule": "self-comparison",
+    "message": "both sides of == are the same expression"
+  }
+]
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		var out bytes.Buffer
+		if err := WriteJSON(&out, tt.issues); err != nil {
+			t.Fatalf("WriteJSON returned error: %s", err)
+		}
+		if out.String() != tt.expected {
+			t.Errorf("wrong output.\nwant=%s\ngot= %s", tt.expected, out.String())
+		}
+	}
+}
+
+func parse(t *testing.T, input string) *ast.Program {
+	t.Helper()
+
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors for %q: %v", input, p.Errors())
+	}
+	return program
+}
@@ -1,17 +1,32 @@
 package main
 
 import (
+	"bytes"
+	"flag"
 	"fmt"
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/doc"
 	"github.com/Devashish08/InterPreter-Compiler/evaluator"
+	"github.com/Devashish08/InterPreter-Compiler/format"
+	"github.com/Devashish08/InterPreter-Compiler/graph"
 	"github.com/Devashish08/InterPreter-Compiler/lexer"
+	"github.com/Devashish08/InterPreter-Compiler/lint"
 	"github.com/Devashish08/InterPreter-Compiler/object"
 	"github.com/Devashish08/InterPreter-Compiler/parser"
 	"github.com/Devashish08/InterPreter-Compiler/repl"
+	"github.com/Devashish08/InterPreter-Compiler/resolver"
+	"github.com/Devashish08/InterPreter-Compiler/typecheck"
 	"io/ioutil"
 	"os"
 	"os/user"
+	"path/filepath"
+	"strings"
 )
 
+// searchPathEnv names the environment variable listing the directories in
+// which imported modules are looked up after the importing file's own.
+const searchPathEnv = "MONKEY_PATH"
+
 func main() {
 	if len(os.Args) < 2 {
 		startRepl()
@@ -21,12 +36,97 @@ func main() {
 	command := os.Args[1]
 	switch command {
 	case "run":
-		if len(os.Args) < 3 {
+		flags := flag.NewFlagSet("run", flag.ExitOnError)
+		traceParser := flags.Bool("trace-parser", false, "write a parser trace to stderr")
+		flags.Parse(os.Args[2:])
+
+		if flags.NArg() < 1 {
 			fmt.Println("Please provide a file to execute")
 			printHelp()
 			os.Exit(1)
 		}
-		runFile(os.Args[2])
+
+		var opts []parser.Option
+		if *traceParser {
+			opts = append(opts, parser.WithTracing(os.Stderr))
+		}
+		runFile(flags.Arg(0), opts...)
+	case "doc":
+		flags := flag.NewFlagSet("doc", flag.ExitOnError)
+		format := flags.String("format", "markdown", "output format: markdown or html")
+		flags.Parse(os.Args[2:])
+
+		if flags.NArg() < 1 {
+			fmt.Println("Please provide a file to document")
+			printHelp()
+			os.Exit(1)
+		}
+
+		docFile(flags.Arg(0), *format)
+	case "fmt":
+		flags := flag.NewFlagSet("fmt", flag.ExitOnError)
+		check := flags.Bool("check", false, "list files whose formatting differs and exit 1 if any")
+		write := flags.Bool("write", false, "write the result to the file instead of stdout")
+		flags.Parse(os.Args[2:])
+
+		if flags.NArg() < 1 {
+			fmt.Println("Please provide a file to format")
+			printHelp()
+			os.Exit(1)
+		}
+
+		if !formatFiles(flags.Args(), *check, *write) {
+			os.Exit(1)
+		}
+	case "check":
+		flags := flag.NewFlagSet("check", flag.ExitOnError)
+		types := flags.Bool("types", false, "print the inferred type of each top-level binding")
+		flags.Parse(os.Args[2:])
+
+		if flags.NArg() < 1 {
+			fmt.Println("Please provide a file to check")
+			printHelp()
+			os.Exit(1)
+		}
+
+		checkFile(flags.Arg(0), *types)
+	case "graph":
+		flags := flag.NewFlagSet("graph", flag.ExitOnError)
+		runtime := flags.Bool("runtime", false, "run the program and graph its environments instead of its AST")
+		flags.Parse(os.Args[2:])
+
+		if flags.NArg() < 1 {
+			fmt.Println("Please provide a file to graph")
+			printHelp()
+			os.Exit(1)
+		}
+
+		graphFile(flags.Arg(0), *runtime)
+	case "lint":
+		flags := flag.NewFlagSet("lint", flag.ExitOnError)
+		format := flags.String("format", "text", "output format: text or json")
+		enable := flags.String("enable", "", "comma-separated rule IDs to run instead of all rules")
+		disable := flags.String("disable", "", "comma-separated rule IDs not to run")
+		list := flags.Bool("list", false, "list the rules and exit")
+		flags.Parse(os.Args[2:])
+
+		if *list {
+			for _, rule := range lint.Rules {
+				fmt.Printf("%-18s %s\n", rule.ID, rule.Description)
+			}
+			return
+		}
+
+		if flags.NArg() < 1 {
+			fmt.Println("Please provide a file to lint")
+			printHelp()
+			os.Exit(1)
+		}
+
+		config := lintConfig(*enable, *disable)
+		if !lintFiles(flags.Args(), config, *format) {
+			os.Exit(1)
+		}
 	case "repl":
 		startRepl()
 	case "help":
@@ -48,40 +148,344 @@ func startRepl() {
 	repl.Start(os.Stdin, os.Stdout)
 }
 
-func runFile(path string) {
+func runFile(path string, opts ...parser.Option) {
+	evalFile(path, opts...)
+}
+
+// evalFile runs the program in the file at path and returns its global
+// environment. It exits on parser, macro expansion and runtime errors.
+func evalFile(path string, opts ...parser.Option) *object.Environment {
+	program := loadProgram(path, opts...)
+
+	loader := evaluator.NewModuleLoader(searchPath())
+	loader.ParserOptions = opts
+	loader.CheckNames = resolver.Check
+	env := loader.NewEnvironment(path)
+
+	evaluated := evaluator.Eval(program, env)
+	if evaluated == nil {
+		fmt.Println("Error: evaluation returned nil")
+		os.Exit(1)
+	}
+
+	if err, ok := evaluated.(*object.Error); ok {
+		if err.Line > 0 {
+			fmt.Printf("Runtime error at line %d, column %d: %s\n",
+				err.Line, err.Column, err.Inspect())
+		} else {
+			fmt.Printf("Runtime error: %s\n", err.Inspect())
+		}
+		os.Exit(1)
+	}
+
+	return env
+}
+
+// loadProgram parses the program in the file at path, expands its macros
+// and resolves its names. It exits on errors.
+func loadProgram(path string, opts ...parser.Option) *ast.Program {
 	input, err := ioutil.ReadFile(path)
 	if err != nil {
 		fmt.Printf("Error reading file: %s\n", err)
 		os.Exit(1)
 	}
 
-	env := object.NewEnvironment()
-	l := lexer.New(string(input))
-	p := parser.New(l)
+	p := parser.New(lexer.New(string(input)), opts...)
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		printParserErrors(p.Errors())
+		os.Exit(1)
+	}
+
+	macroEnv := object.NewEnvironment()
+	evaluator.DefineMacros(program, macroEnv)
+	expanded, err := evaluator.ExpandMacros(program, macroEnv)
+	if err != nil {
+		fmt.Printf("Macro expansion error: %s\n", err)
+		os.Exit(1)
+	}
+
+	program, ok := expanded.(*ast.Program)
+	if !ok || !checkNames(program) {
+		os.Exit(1)
+	}
+
+	return program
+}
+
+// checkFile infers the types of the program in the file at path and prints
+// the type errors found, exiting 1 if there are any. With types it first
+// prints the type of each top-level let binding.
+func checkFile(path string, types bool) {
+	result := typecheck.Check(loadProgram(path))
+
+	if types {
+		for _, b := range result.Bindings {
+			fmt.Printf("%s: %s\n", b.Name, b.Type)
+		}
+	}
+
+	if len(result.Errors) == 0 {
+		return
+	}
+
+	fmt.Printf("Type errors:\n")
+	for _, e := range result.Errors {
+		fmt.Printf("\t%s\n", e)
+	}
+	os.Exit(1)
+}
+
+// checkNames resolves the names of program before it runs. It prints
+// warnings to stderr and errors to stdout, and reports whether there were
+// no errors.
+func checkNames(program *ast.Program) bool {
+	result := resolver.Resolve(program)
+
+	for _, d := range result.Diagnostics {
+		if d.Warning() {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", d)
+		}
+	}
+
+	errors := result.Errors()
+	if len(errors) == 0 {
+		return true
+	}
+
+	fmt.Printf("Name errors:\n")
+	for _, d := range errors {
+		fmt.Printf("\t%s\n", d)
+	}
+	return false
+}
+
+func docFile(path, format string) {
+	input, err := ioutil.ReadFile(path)
+	if err != nil {
+		fmt.Printf("Error reading file: %s\n", err)
+		os.Exit(1)
+	}
 
+	p := parser.New(lexer.New(string(input)))
 	program := p.ParseProgram()
 	if len(p.Errors()) != 0 {
 		printParserErrors(p.Errors())
 		os.Exit(1)
 	}
 
-	evaluated := evaluator.Eval(program, env)
-	if evaluated == nil {
-		fmt.Println("Error: evaluation returned nil")
+	functions := doc.Functions(program)
+	title := filepath.Base(path)
+
+	switch format {
+	case "markdown", "md":
+		err = doc.Markdown(os.Stdout, title, functions)
+	case "html":
+		err = doc.HTML(os.Stdout, title, functions)
+	default:
+		fmt.Printf("Unknown doc format: %s\n", format)
 		os.Exit(1)
 	}
 
-	if evaluated.Type() == object.ERROR_OBJ {
-		fmt.Printf("Runtime error: %s\n", evaluated.Inspect())
+	if err != nil {
+		fmt.Printf("Error writing documentation: %s\n", err)
 		os.Exit(1)
 	}
 }
 
+// graphFile prints a DOT graph of the AST of the program in the file at
+// path or, with runtime, of the environments left after running it.
+func graphFile(path string, runtime bool) {
+	var err error
+
+	if runtime {
+		err = graph.Environment(os.Stdout, evalFile(path))
+	} else {
+		input, readErr := ioutil.ReadFile(path)
+		if readErr != nil {
+			fmt.Printf("Error reading file: %s\n", readErr)
+			os.Exit(1)
+		}
+
+		p := parser.New(lexer.New(string(input)))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			printParserErrors(p.Errors())
+			os.Exit(1)
+		}
+
+		err = graph.AST(os.Stdout, program)
+	}
+
+	if err != nil {
+		fmt.Printf("Error writing graph: %s\n", err)
+		os.Exit(1)
+	}
+}
+
+// formatFiles formats each file in paths. With check it lists the files
+// that are not formatted; with write it rewrites them in place; otherwise
+// it prints the formatted source. It reports whether every file parsed and,
+// with check, was already formatted.
+func formatFiles(paths []string, check, write bool) bool {
+	ok := true
+
+	for _, path := range paths {
+		input, err := ioutil.ReadFile(path)
+		if err != nil {
+			fmt.Printf("Error reading file: %s\n", err)
+			ok = false
+			continue
+		}
+
+		output, err := format.Source(input)
+		if err != nil {
+			fmt.Printf("%s:\n", path)
+			printParserErrors(strings.Split(err.Error(), "\n"))
+			ok = false
+			continue
+		}
+
+		switch {
+		case check:
+			if !bytes.Equal(input, output) {
+				fmt.Println(path)
+				ok = false
+			}
+		case write:
+			if bytes.Equal(input, output) {
+				continue
+			}
+			if err := ioutil.WriteFile(path, output, 0644); err != nil {
+				fmt.Printf("Error writing file: %s\n", err)
+				ok = false
+			}
+		default:
+			os.Stdout.Write(output)
+		}
+	}
+
+	return ok
+}
+
+// lintConfig builds the lint configuration from the comma-separated rule
+// IDs of the --enable and --disable flags. It exits on an unknown ID.
+func lintConfig(enable, disable string) lint.Config {
+	config := lint.Config{Disabled: make(map[string]bool)}
+
+	if enable != "" {
+		enabled := ruleIDs(enable)
+		for _, rule := range lint.Rules {
+			if !enabled[rule.ID] {
+				config.Disabled[rule.ID] = true
+			}
+		}
+	}
+	for id := range ruleIDs(disable) {
+		config.Disabled[id] = true
+	}
+
+	return config
+}
+
+func ruleIDs(list string) map[string]bool {
+	ids := make(map[string]bool)
+	for _, id := range strings.Split(list, ",") {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if !lint.IsRule(id) {
+			fmt.Printf("Unknown lint rule: %s\n", id)
+			os.Exit(1)
+		}
+		ids[id] = true
+	}
+	return ids
+}
+
+// lintFiles lints each file in paths and prints the issues found in all
+// of them in the given format. It reports whether every file parsed and no
+// issues were found.
+func lintFiles(paths []string, config lint.Config, format string) bool {
+	if format != "text" && format != "json" {
+		fmt.Printf("Unknown lint format: %s\n", format)
+		os.Exit(1)
+	}
+
+	ok := true
+	issues := []lint.Issue{}
+
+	for _, path := range paths {
+		input, err := ioutil.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading file: %s\n", err)
+			ok = false
+			continue
+		}
+
+		p := parser.New(lexer.New(string(input)))
+		program := p.ParseProgram()
+		if len(p.Errors()) != 0 {
+			fmt.Fprintf(os.Stderr, "%s: parser errors:\n", path)
+			for _, msg := range p.Errors() {
+				fmt.Fprintf(os.Stderr, "\t%s\n", msg)
+			}
+			ok = false
+			continue
+		}
+
+		for _, issue := range lint.Lint(program, config) {
+			issue.File = path
+			issues = append(issues, issue)
+		}
+	}
+
+	var err error
+	if format == "json" {
+		err = lint.WriteJSON(os.Stdout, issues)
+	} else {
+		err = lint.WriteText(os.Stdout, issues)
+	}
+	if err != nil {
+		fmt.Printf("Error writing lint issues: %s\n", err)
+		os.Exit(1)
+	}
+
+	return ok && len(issues) == 0
+}
+
+func searchPath() []string {
+	value := os.Getenv(searchPathEnv)
+	if value == "" {
+		return nil
+	}
+	return filepath.SplitList(value)
+}
+
 func printHelp() {
 	fmt.Println("Usage:")
 	fmt.Println("  interpreter run <filename>  - Execute a Monkey program file")
+	fmt.Println("      --trace-parser          - Trace the parser to stderr")
+	fmt.Println("  interpreter check <file>    - Infer types and report type errors")
+	fmt.Println("      --types                 - Print the type of each top-level binding")
+	fmt.Println("  interpreter doc <filename>  - Print API docs from /// comments")
+	fmt.Println("      --format markdown|html  - Output format (default markdown)")
+	fmt.Println("  interpreter fmt <files>     - Format Monkey source files")
+	fmt.Println("      --check                 - List unformatted files, exit 1 if any")
+	fmt.Println("      --write                 - Rewrite the files in place")
+	fmt.Println("  interpreter graph <file>    - Print the AST as a Graphviz DOT graph")
+	fmt.Println("      --runtime               - Run it and graph environments and closures")
+	fmt.Println("  interpreter lint <files>    - Report suspicious code, exit 1 if any")
+	fmt.Println("      --format text|json      - Output format (default text)")
+	fmt.Println("      --enable <rules>        - Run only these comma-separated rule IDs")
+	fmt.Println("      --disable <rules>       - Skip these comma-separated rule IDs")
+	fmt.Println("      --list                  - List the rules and their IDs")
 	fmt.Println("  interpreter repl           - Start the interactive REPL")
 	fmt.Println("  interpreter help           - Show this help message")
+	fmt.Println()
+	fmt.Println("Modules are looked up next to the importing file, then in the")
+	fmt.Println("directories listed in " + searchPathEnv + ".")
 }
 
 func printParserErrors(errors []string) {
@@ -1,19 +1,89 @@
 package object
 
+import (
+	"sort"
+
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+)
+
 func NewEnclosedEnvironment(outer *Environment) *Environment {
 	env := NewEnvironment()
 	env.outer = outer
+	env.frame = outer.frame
+	env.module = outer.module
+	return env
+}
+
+// NewModuleEnvironment creates the top-level environment of module and
+// stores it in module.Env. Environments enclosed by it belong to the
+// module too.
+func NewModuleEnvironment(module *Module) *Environment {
+	env := NewEnvironment()
+	env.module = module
+	module.Env = env
+	return env
+}
+
+// NewFunctionEnvironment creates the environment for a single function
+// call. Unlike NewEnclosedEnvironment it starts a new Frame.
+func NewFunctionEnvironment(outer *Environment) *Environment {
+	env := NewEnvironment()
+	env.outer = outer
+	env.frame = &Frame{}
+	env.module = outer.module
 	return env
 }
 
 func NewEnvironment() *Environment {
 	s := make(map[string]Object)
-	return &Environment{store: s, outer: nil}
+	return &Environment{store: s, constants: make(map[string]bool), outer: nil}
 }
 
 type Environment struct {
-	store map[string]Object
-	outer *Environment
+	store     map[string]Object
+	constants map[string]bool // names declared with `const` in this scope
+	exports   map[string]bool // names exported from a module's top level
+	outer     *Environment
+	frame     *Frame  // nil outside of function calls
+	module    *Module // the module whose code created the environment
+}
+
+// Frame holds the state of one function call. It is shared by every
+// environment created while the call runs.
+type Frame struct {
+	deferred []Deferred
+
+	// Yield is set when the frame runs a generator function. It hands a
+	// value to the generator's consumer and blocks until the next value is
+	// requested. It returns false when the generator was closed instead.
+	Yield func(value Object) bool
+}
+
+// Deferred is an expression registered with `defer` together with the
+// environment it is evaluated in.
+type Deferred struct {
+	Expression ast.Expression
+	Env        *Environment
+}
+
+// Defer registers d to run when the frame's function call returns.
+func (f *Frame) Defer(d Deferred) {
+	f.deferred = append(f.deferred, d)
+}
+
+// Deferred returns the registered expressions, most recent first.
+func (f *Frame) Deferred() []Deferred {
+	out := make([]Deferred, len(f.deferred))
+	for i, d := range f.deferred {
+		out[len(f.deferred)-1-i] = d
+	}
+	return out
+}
+
+// Frame returns the frame of the function call this environment belongs
+// to, or nil at the top level.
+func (e *Environment) Frame() *Frame {
+	return e.frame
 }
 
 func (e *Environment) Get(name string) (Object, bool) {
@@ -28,3 +98,65 @@ func (e *Environment) Set(name string, val Object) Object {
 	e.store[name] = val
 	return val
 }
+
+// SetConst binds name like Set and records that the binding is constant.
+func (e *Environment) SetConst(name string, val Object) Object {
+	e.constants[name] = true
+	return e.Set(name, val)
+}
+
+// IsConst reports whether name was declared with `const` in this
+// environment itself. Constants of outer environments may be shadowed.
+func (e *Environment) IsConst(name string) bool {
+	return e.constants[name]
+}
+
+// Export marks the binding of name as visible to importers.
+func (e *Environment) Export(name string) {
+	if e.exports == nil {
+		e.exports = make(map[string]bool)
+	}
+	e.exports[name] = true
+}
+
+// IsExported reports whether name was exported from this environment.
+func (e *Environment) IsExported(name string) bool {
+	return e.exports[name]
+}
+
+// IsTopLevel reports whether the environment is not enclosed by another,
+// like the global environment of a program or module.
+func (e *Environment) IsTopLevel() bool {
+	return e.outer == nil
+}
+
+// Outer returns the environment enclosing e, or nil at the top level.
+func (e *Environment) Outer() *Environment {
+	return e.outer
+}
+
+// Names returns the names bound in e itself, not in its outer
+// environments, in sorted order.
+func (e *Environment) Names() []string {
+	names := make([]string, 0, len(e.store))
+	for name := range e.store {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Module returns the module whose code created the environment, or nil.
+func (e *Environment) Module() *Module {
+	return e.module
+}
+
+// Resolve returns the innermost environment that binds name.
+func (e *Environment) Resolve(name string) (*Environment, bool) {
+	for env := e; env != nil; env = env.outer {
+		if _, ok := env.store[name]; ok {
+			return env, true
+		}
+	}
+	return nil, false
+}
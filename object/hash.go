@@ -0,0 +1,187 @@
+package object
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// minBuckets is the number of buckets of a hash's first table. It must be
+// a power of two.
+const minBuckets = 8
+
+type HashPair struct {
+	Key   Object
+	Value Object
+}
+
+// Hash maps hashable keys to values. It keeps its pairs in the order in
+// which their keys were first set, which is the order of Pairs, Inspect and
+// iteration.
+//
+// The pairs are found through a hash table with separate chaining. A
+// HashKey only picks the bucket: keys in the same bucket are told apart by
+// Equal, so different keys whose HashKeys collide are both kept. The table
+// doubles when it holds more pairs than buckets.
+type Hash struct {
+	pairs   []HashPair
+	buckets [][]hashEntry
+	Frozen  bool // set by freeze(); frozen hashes reject key assignment
+}
+
+// hashEntry locates one pair of a Hash. The key's HashKey is kept to
+// compare it cheaply before calling Equal and to rehash without calling
+// HashKey again.
+type hashEntry struct {
+	key   HashKey
+	index int // position of the pair in Hash.pairs
+}
+
+// NewHash returns an empty hash.
+func NewHash() *Hash {
+	return &Hash{}
+}
+
+// Get returns the value bound to key.
+func (h *Hash) Get(key Hashable) (Object, bool) {
+	hashKey := key.HashKey()
+	if i, ok := h.find(key, hashKey); ok {
+		return h.pairs[i].Value, true
+	}
+	return nil, false
+}
+
+// Set binds key to value. A key that is already bound keeps its place in
+// the order.
+func (h *Hash) Set(key Hashable, value Object) {
+	hashKey := key.HashKey()
+	if i, ok := h.find(key, hashKey); ok {
+		h.pairs[i].Value = value
+		return
+	}
+
+	if len(h.pairs) >= len(h.buckets) {
+		h.grow()
+	}
+
+	b := h.bucket(hashKey)
+	h.buckets[b] = append(h.buckets[b], hashEntry{key: hashKey, index: len(h.pairs)})
+	h.pairs = append(h.pairs, HashPair{Key: key, Value: value})
+}
+
+// Len returns the number of pairs in the hash.
+func (h *Hash) Len() int {
+	return len(h.pairs)
+}
+
+// Pairs returns the pairs of the hash in insertion order. The slice is
+// shared with the hash and must not be modified.
+func (h *Hash) Pairs() []HashPair {
+	return h.pairs
+}
+
+// find returns the position in h.pairs of the pair whose key equals key.
+func (h *Hash) find(key Object, hashKey HashKey) (int, bool) {
+	if len(h.buckets) == 0 {
+		return 0, false
+	}
+
+	for _, entry := range h.buckets[h.bucket(hashKey)] {
+		if entry.key == hashKey && Equal(h.pairs[entry.index].Key, key) {
+			return entry.index, true
+		}
+	}
+	return 0, false
+}
+
+// bucket returns the bucket of hashKey: the top bits of its value times a
+// large odd constant, which spreads keys that differ only in their low
+// bits, such as consecutive integers, over the table.
+func (h *Hash) bucket(hashKey HashKey) int {
+	shift := 64 - bitLength(len(h.buckets)-1)
+	return int((hashKey.Value * 0x9E3779B97F4A7C15) >> shift)
+}
+
+// grow doubles the number of buckets and redistributes the entries.
+func (h *Hash) grow() {
+	size := 2 * len(h.buckets)
+	if size < minBuckets {
+		size = minBuckets
+	}
+
+	old := h.buckets
+	h.buckets = make([][]hashEntry, size)
+	for _, bucket := range old {
+		for _, entry := range bucket {
+			b := h.bucket(entry.key)
+			h.buckets[b] = append(h.buckets[b], entry)
+		}
+	}
+}
+
+// bitLength returns the number of bits needed to represent n.
+func bitLength(n int) uint {
+	var bits uint
+	for ; n > 0; n >>= 1 {
+		bits++
+	}
+	return bits
+}
+
+func (h *Hash) Type() ObjectType { return HASH_OBJ }
+func (h *Hash) Inspect() string {
+	var out bytes.Buffer
+
+	pairs := []string{}
+	for _, pair := range h.pairs {
+		pairs = append(pairs, fmt.Sprintf("%s: %s",
+			pair.Key.Inspect(), pair.Value.Inspect()))
+	}
+
+	out.WriteString("{")
+	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString("}")
+
+	return out.String()
+}
+
+// Equal compares two values structurally: integers, booleans and strings
+// by value, arrays and enum values element by element, everything else by
+// identity. Keys that are Equal have the same HashKey.
+func Equal(a, b Object) bool {
+	switch a := a.(type) {
+	case *Integer:
+		b, ok := b.(*Integer)
+		return ok && a.Value == b.Value
+	case *Boolean:
+		b, ok := b.(*Boolean)
+		return ok && a.Value == b.Value
+	case *String:
+		b, ok := b.(*String)
+		return ok && a.Value == b.Value
+	case *Array:
+		b, ok := b.(*Array)
+		if !ok || len(a.Elements) != len(b.Elements) {
+			return false
+		}
+		for i := range a.Elements {
+			if !Equal(a.Elements[i], b.Elements[i]) {
+				return false
+			}
+		}
+		return true
+	case *EnumValue:
+		b, ok := b.(*EnumValue)
+		if !ok || a.Variant != b.Variant {
+			return false
+		}
+		for i := range a.Values {
+			if !Equal(a.Values[i], b.Values[i]) {
+				return false
+			}
+		}
+		return true
+	default:
+		return a == b
+	}
+}
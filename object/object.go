@@ -8,6 +8,7 @@ including:
   - Composite types (Array, Hash)
   - Functions (Function, Builtin)
   - Special types (Null, Return, Error)
+  - ErrorValue: A caught error that can be inspected and rethrown
 
 Key interfaces and types:
 
@@ -18,6 +19,12 @@ Key interfaces and types:
   - Builtin: Represents built-in functions
   - Array: Represents array literals
   - Hash: Represents hash literals
+  - Generator: The suspended call of a function that contains `yield`
+  - Range: A lazy sequence of integers produced by `a..b` and `a..=b`
+  - Class, Instance: Represent user-defined types and their values
+  - Enum, EnumVariant, EnumValue: Represent tagged unions
+  - Quote, Macro: Represent unevaluated code and macro definitions
+  - Module, Importer: Represent imported source files and how they are loaded
 
 Each type implements:
 
@@ -34,6 +41,8 @@ import (
 	"bytes"
 	"fmt"
 	"hash/fnv"
+	"math"
+	"sort"
 	"strings"
 
 	"github.com/Devashish08/InterPreter-Compiler/ast"
@@ -47,6 +56,8 @@ const (
 	NULL_OBJ  = "NULL"
 	ERROR_OBJ = "ERROR"
 
+	ERROR_VALUE_OBJ = "ERROR_VALUE"
+
 	INTEGER_OBJ = "INTEGER"
 	BOOLEAN_OBJ = "BOOLEAN"
 	STRING_OBJ  = "STRING"
@@ -58,6 +69,22 @@ const (
 
 	ARRAY_OBJ = "ARRAY"
 	HASH_OBJ  = "HASH"
+	RANGE_OBJ = "RANGE"
+
+	GENERATOR_OBJ = "GENERATOR"
+
+	MODULE_OBJ = "MODULE"
+
+	CLASS_OBJ    = "CLASS"
+	INSTANCE_OBJ = "INSTANCE"
+	SUPER_OBJ    = "SUPER"
+
+	ENUM_OBJ         = "ENUM"
+	ENUM_VARIANT_OBJ = "ENUM_VARIANT"
+	ENUM_VALUE_OBJ   = "ENUM_VALUE"
+
+	QUOTE_OBJ = "QUOTE"
+	MACRO_OBJ = "MACRO"
 )
 
 type HashKey struct {
@@ -66,6 +93,7 @@ type HashKey struct {
 }
 
 type Hashable interface {
+	Object
 	HashKey() HashKey
 }
 
@@ -114,17 +142,42 @@ type ReturnValue struct {
 func (rv *ReturnValue) Type() ObjectType { return RETURN_VALUE_OBJ }
 func (rv *ReturnValue) Inspect() string  { return rv.Value.Inspect() }
 
+// Error unwinds evaluation until a try statement catches it or it reaches
+// the top of the program.
 type Error struct {
 	Message string
+	Kind    string // e.g. RuntimeError for interpreter errors
+	Line    int    // position where the error was raised, 0 if unknown
+	Column  int
+	Value   Object // the thrown value when it was not a string or error
 }
 
 func (e *Error) Type() ObjectType { return ERROR_OBJ }
 func (e *Error) Inspect() string  { return "ERROR: " + e.Message }
 
+// ErrorValue is an error held as an ordinary value, such as the one bound
+// by a catch clause. Unlike Error it does not unwind evaluation.
+type ErrorValue struct {
+	Error *Error
+}
+
+func (ev *ErrorValue) Type() ObjectType { return ERROR_VALUE_OBJ }
+func (ev *ErrorValue) Inspect() string {
+	out := ev.Error.Kind + ": " + ev.Error.Message
+	if ev.Error.Line > 0 {
+		out += fmt.Sprintf(" (line %d, column %d)", ev.Error.Line, ev.Error.Column)
+	}
+	return out
+}
+
 type Function struct {
-	Parameters []*ast.Identifier
-	Body       *ast.BlockStatement
-	Env        *Environment
+	Parameters     []*ast.Identifier
+	ParameterTypes []*ast.TypeAnnotation // parallel to Parameters, nil when unannotated
+	ReturnType     *ast.TypeAnnotation
+	Body           *ast.BlockStatement
+	Env            *Environment
+	Generator      bool   // calling it returns a Generator instead of running Body
+	Doc            string // doc comment of the let statement defining it
 }
 
 func (f *Function) Type() ObjectType { return FUNCTION_OBJ }
@@ -161,6 +214,14 @@ func (s *String) HashKey() HashKey {
 
 type Builtin struct {
 	Fn BuiltinFunction
+	// MinArgs and MaxArgs bound the number of arguments Fn accepts.
+	// MaxArgs is -1 when there is no upper bound.
+	MinArgs, MaxArgs int
+}
+
+// AcceptsArgs reports whether the builtin can be called with n arguments.
+func (b *Builtin) AcceptsArgs(n int) bool {
+	return n >= b.MinArgs && (b.MaxArgs < 0 || n <= b.MaxArgs)
 }
 
 func (b *Builtin) Type() ObjectType { return BUILTIN_OBJ }
@@ -168,6 +229,7 @@ func (b *Builtin) Inspect() string  { return "builtin function" }
 
 type Array struct {
 	Elements []Object
+	Frozen   bool // set by freeze(); frozen arrays reject element assignment
 }
 
 func (ao *Array) Type() ObjectType { return ARRAY_OBJ }
@@ -186,28 +248,277 @@ func (ao *Array) Inspect() string {
 	return out.String()
 }
 
-type HashPair struct {
-	Key   Object
-	Value Object
+// Range is the integer sequence from Start up to End. Its elements are
+// computed on demand rather than stored.
+type Range struct {
+	Start     int64
+	End       int64
+	Inclusive bool // whether End itself is part of the range
 }
 
-type Hash struct {
-	Pairs map[HashKey]HashPair
+func (r *Range) Type() ObjectType { return RANGE_OBJ }
+func (r *Range) Inspect() string {
+	op := ".."
+	if r.Inclusive {
+		op = "..="
+	}
+	return fmt.Sprintf("%d%s%d", r.Start, op, r.End)
 }
 
-func (h *Hash) Type() ObjectType { return HASH_OBJ }
-func (h *Hash) Inspect() string {
+// Len returns the number of integers in the range. A range wider than
+// math.MaxInt64, such as -9223372036854775807..9223372036854775807, is
+// reported as math.MaxInt64 long.
+func (r *Range) Len() int64 {
+	if r.End < r.Start || r.End == r.Start && !r.Inclusive {
+		return 0
+	}
+
+	// The difference is computed unsigned, where it cannot overflow.
+	n := uint64(r.End) - uint64(r.Start)
+	if n >= math.MaxInt64 {
+		return math.MaxInt64
+	}
+	if r.Inclusive {
+		n++
+	}
+	return int64(n)
+}
+
+// At returns the i-th integer of the range. i must be in [0, Len()).
+func (r *Range) At(i int64) int64 {
+	return r.Start + i
+}
+
+// Index returns the i-th integer of the range, counting from the end when
+// i is negative, and reports whether there is one. Unlike At it also works
+// for ranges longer than Len reports.
+func (r *Range) Index(i int64) (int64, bool) {
+	base := r.Start
+	if i < 0 {
+		base = r.End
+		if r.Inclusive {
+			i++
+		}
+	}
+
+	// Past either end of int64 the result cannot be in the range.
+	if i > 0 && base > math.MaxInt64-i || i < 0 && base < math.MinInt64-i {
+		return 0, false
+	}
+
+	n := base + i
+	return n, r.Contains(n)
+}
+
+// Contains reports whether n is one of the range's integers.
+func (r *Range) Contains(n int64) bool {
+	return n >= r.Start && (n < r.End || r.Inclusive && n == r.End)
+}
+
+// Generator is returned by calling a generator function. The function
+// body runs only as values are requested from it.
+type Generator struct {
+	// Next resumes the function until its next yield and returns the
+	// yielded value and true. Once the function has finished it returns
+	// false, together with the error that ended it, if any.
+	Next func() (Object, bool)
+	// Close stops a suspended generator, running its deferred expressions
+	// and finally blocks. Later calls to Next report that it has finished.
+	Close func()
+}
+
+func (g *Generator) Type() ObjectType { return GENERATOR_OBJ }
+func (g *Generator) Inspect() string  { return "generator" }
+
+type Class struct {
+	Name       string
+	Superclass *Class
+	Methods    map[string]*Function
+}
+
+func (c *Class) Type() ObjectType { return CLASS_OBJ }
+func (c *Class) Inspect() string  { return "class " + c.Name }
+
+// FindMethod looks up a method on the class and its ancestors. It also
+// returns the class that defines the method, which is where `super`
+// lookups continue from.
+func (c *Class) FindMethod(name string) (*Function, *Class) {
+	for class := c; class != nil; class = class.Superclass {
+		if method, ok := class.Methods[name]; ok {
+			return method, class
+		}
+	}
+	return nil, nil
+}
+
+type Instance struct {
+	Class  *Class
+	Fields map[string]Object
+}
+
+func (i *Instance) Type() ObjectType { return INSTANCE_OBJ }
+func (i *Instance) Inspect() string {
 	var out bytes.Buffer
 
-	pairs := []string{}
-	for _, pair := range h.Pairs {
-		pairs = append(pairs, fmt.Sprintf("%s: %s",
-			pair.Key.Inspect(), pair.Value.Inspect()))
+	names := make([]string, 0, len(i.Fields))
+	for name := range i.Fields {
+		names = append(names, name)
 	}
+	sort.Strings(names)
 
+	fields := []string{}
+	for _, name := range names {
+		fields = append(fields, name+": "+i.Fields[name].Inspect())
+	}
+
+	out.WriteString(i.Class.Name)
 	out.WriteString("{")
-	out.WriteString(strings.Join(pairs, ", "))
+	out.WriteString(strings.Join(fields, ", "))
 	out.WriteString("}")
 
 	return out.String()
 }
+
+// Super is bound to `super` inside methods of a class that extends
+// another one. Member access on it finds methods starting at Class and
+// binds them to Instance.
+type Super struct {
+	Instance *Instance
+	Class    *Class
+}
+
+func (s *Super) Type() ObjectType { return SUPER_OBJ }
+func (s *Super) Inspect() string  { return "super " + s.Class.Name }
+
+type Enum struct {
+	Name     string
+	Variants map[string]*EnumVariant
+}
+
+func (e *Enum) Type() ObjectType { return ENUM_OBJ }
+func (e *Enum) Inspect() string  { return "enum " + e.Name }
+
+// EnumVariant is the constructor for one case of an enum. Variants
+// without fields have a single shared Value instead.
+type EnumVariant struct {
+	Enum   *Enum
+	Name   string
+	Fields []string
+	Value  *EnumValue // set when the variant has no fields
+}
+
+func (ev *EnumVariant) Type() ObjectType { return ENUM_VARIANT_OBJ }
+func (ev *EnumVariant) Inspect() string {
+	return ev.Enum.Name + "." + ev.Name + "(" + strings.Join(ev.Fields, ", ") + ")"
+}
+
+type EnumValue struct {
+	Variant *EnumVariant
+	Values  []Object
+}
+
+func (ev *EnumValue) Type() ObjectType { return ENUM_VALUE_OBJ }
+func (ev *EnumValue) Inspect() string {
+	var out bytes.Buffer
+
+	out.WriteString(ev.Variant.Enum.Name + "." + ev.Variant.Name)
+
+	if len(ev.Variant.Fields) > 0 {
+		values := []string{}
+		for _, v := range ev.Values {
+			values = append(values, v.Inspect())
+		}
+
+		out.WriteString("(")
+		out.WriteString(strings.Join(values, ", "))
+		out.WriteString(")")
+	}
+
+	return out.String()
+}
+func (ev *EnumValue) HashKey() HashKey {
+	h := fnv.New64a()
+	h.Write([]byte(ev.Variant.Enum.Name + "." + ev.Variant.Name))
+
+	for _, v := range ev.Values {
+		if hashable, ok := v.(Hashable); ok {
+			key := hashable.HashKey()
+			fmt.Fprintf(h, "|%s:%d", key.Type, key.Value)
+		} else {
+			fmt.Fprintf(h, "|%s:%s", v.Type(), v.Inspect())
+		}
+	}
+
+	return HashKey{Type: ev.Type(), Value: h.Sum64()}
+}
+
+// Field returns the payload value stored under the given field name.
+func (ev *EnumValue) Field(name string) (Object, bool) {
+	for i, field := range ev.Variant.Fields {
+		if field == name {
+			return ev.Values[i], true
+		}
+	}
+	return nil, false
+}
+
+type Quote struct {
+	Node ast.Node
+}
+
+func (q *Quote) Type() ObjectType { return QUOTE_OBJ }
+func (q *Quote) Inspect() string {
+	return "QUOTE(" + q.Node.String() + ")"
+}
+
+type Macro struct {
+	Parameters []*ast.Identifier
+	Body       *ast.BlockStatement
+	Env        *Environment
+}
+
+func (m *Macro) Type() ObjectType { return MACRO_OBJ }
+func (m *Macro) Inspect() string {
+	var out bytes.Buffer
+
+	params := []string{}
+	for _, p := range m.Parameters {
+		params = append(params, p.String())
+	}
+
+	out.WriteString("macro")
+	out.WriteString("(")
+	out.WriteString(strings.Join(params, ", "))
+	out.WriteString(") {\n")
+	out.WriteString(m.Body.String())
+	out.WriteString("\n}")
+
+	return out.String()
+}
+
+// Importer loads the modules named by import statements. from is the path
+// of the importing file, or empty when there is none, as in the REPL. It
+// returns a *Module or an *Error.
+type Importer interface {
+	Import(name, from string) Object
+}
+
+// Module is a source file evaluated in its own environment. Only the
+// bindings it exports are visible to importers.
+type Module struct {
+	Name     string // the path as written in the import statement
+	Path     string // the resolved file, empty for the REPL
+	Env      *Environment
+	Importer Importer // loads the modules this one imports
+}
+
+func (m *Module) Type() ObjectType { return MODULE_OBJ }
+func (m *Module) Inspect() string  { return "module " + m.Name }
+
+// Get returns an exported binding of the module.
+func (m *Module) Get(name string) (Object, bool) {
+	if !m.Env.IsExported(name) {
+		return nil, false
+	}
+	return m.Env.Get(name)
+}
@@ -1,6 +1,10 @@
 package object
 
-import "testing"
+import (
+	"fmt"
+	"math"
+	"testing"
+)
 
 func TestStringHashKey(t *testing.T) {
 	hello1 := &String{Value: "Hello World"}
@@ -58,3 +62,229 @@ func TestIntegerHashKey(t *testing.T) {
 		t.Errorf("integers with twoerent content have same hash keys")
 	}
 }
+
+func TestEnumValueHashKey(t *testing.T) {
+	enum := &Enum{Name: "Shape", Variants: map[string]*EnumVariant{}}
+	circle := &EnumVariant{Enum: enum, Name: "Circle", Fields: []string{"r"}}
+	square := &EnumVariant{Enum: enum, Name: "Square", Fields: []string{"r"}}
+
+	one1 := &EnumValue{Variant: circle, Values: []Object{&Integer{Value: 1}}}
+	one2 := &EnumValue{Variant: circle, Values: []Object{&Integer{Value: 1}}}
+	two := &EnumValue{Variant: circle, Values: []Object{&Integer{Value: 2}}}
+	other := &EnumValue{Variant: square, Values: []Object{&Integer{Value: 1}}}
+
+	if one1.HashKey() != one2.HashKey() {
+		t.Errorf("enum values with same payload have different hash keys")
+	}
+
+	if one1.HashKey() == two.HashKey() {
+		t.Errorf("enum values with different payloads have same hash keys")
+	}
+
+	if one1.HashKey() == other.HashKey() {
+		t.Errorf("enum values of different variants have same hash keys")
+	}
+}
+
+func TestHashInsertionOrder(t *testing.T) {
+	hash := NewHash()
+	hash.Set(&String{Value: "zebra"}, &Integer{Value: 1})
+	hash.Set(&Integer{Value: 2}, &Integer{Value: 2})
+	hash.Set(&Boolean{Value: true}, &Integer{Value: 3})
+	hash.Set(&String{Value: "apple"}, &Integer{Value: 4})
+	hash.Set(&String{Value: "zebra"}, &Integer{Value: 5})
+
+	if hash.Len() != 4 {
+		t.Fatalf("hash has wrong length. want=4, got=%d", hash.Len())
+	}
+
+	expected := "{zebra: 5, 2: 2, true: 3, apple: 4}"
+	for i := 0; i < 10; i++ {
+		if hash.Inspect() != expected {
+			t.Fatalf("wrong Inspect. want=%q, got=%q", expected, hash.Inspect())
+		}
+	}
+
+	value, ok := hash.Get(&String{Value: "zebra"})
+	if !ok || value.Inspect() != "5" {
+		t.Errorf("Get(zebra) wrong. got=%v, %t", value, ok)
+	}
+	if _, ok := hash.Get(&String{Value: "missing"}); ok {
+		t.Errorf("Get(missing) found a value")
+	}
+}
+
+// collidingString is a string whose HashKey is the same for every value.
+type collidingString struct {
+	*String
+}
+
+func (c collidingString) HashKey() HashKey {
+	return HashKey{Type: STRING_OBJ, Value: 42}
+}
+
+func TestHashKeyCollisions(t *testing.T) {
+	a := collidingString{&String{Value: "a"}}
+	b := collidingString{&String{Value: "b"}}
+
+	hash := NewHash()
+	hash.Set(a, &Integer{Value: 1})
+	hash.Set(b, &Integer{Value: 2})
+
+	if hash.Len() != 2 {
+		t.Fatalf("colliding keys overwrote each other. len=%d", hash.Len())
+	}
+
+	for _, tt := range []struct {
+		key      Hashable
+		expected string
+	}{
+		{a, "1"},
+		{b, "2"},
+	} {
+		value, ok := hash.Get(tt.key)
+		if !ok || value.Inspect() != tt.expected {
+			t.Errorf("Get(%s) wrong. want=%s, got=%v", tt.key.Inspect(), tt.expected, value)
+		}
+	}
+
+	if _, ok := hash.Get(collidingString{&String{Value: "c"}}); ok {
+		t.Errorf("Get found a value for a key that was never set")
+	}
+}
+
+func TestHashGrowth(t *testing.T) {
+	hash := NewHash()
+	for i := 0; i < 1000; i++ {
+		hash.Set(&Integer{Value: int64(i)}, &String{Value: fmt.Sprint(i)})
+	}
+	for i := 0; i < 1000; i += 2 {
+		hash.Set(&Integer{Value: int64(i)}, &String{Value: "even"})
+	}
+
+	if hash.Len() != 1000 {
+		t.Fatalf("hash has wrong length. want=1000, got=%d", hash.Len())
+	}
+
+	for i, pair := range hash.Pairs() {
+		if pair.Key.(*Integer).Value != int64(i) {
+			t.Fatalf("pairs[%d] has wrong key. got=%s", i, pair.Key.Inspect())
+		}
+	}
+
+	for i := 0; i < 1000; i++ {
+		expected := fmt.Sprint(i)
+		if i%2 == 0 {
+			expected = "even"
+		}
+
+		value, ok := hash.Get(&Integer{Value: int64(i)})
+		if !ok || value.Inspect() != expected {
+			t.Errorf("Get(%d) wrong. want=%s, got=%v", i, expected, value)
+		}
+	}
+}
+
+func TestEnvironmentConstants(t *testing.T) {
+	outer := NewEnvironment()
+	outer.SetConst("a", &Integer{Value: 1})
+	outer.Set("b", &Integer{Value: 2})
+
+	if !outer.IsConst("a") {
+		t.Errorf("a is not constant in outer environment")
+	}
+	if outer.IsConst("b") {
+		t.Errorf("b is constant in outer environment")
+	}
+
+	inner := NewEnclosedEnvironment(outer)
+	if inner.IsConst("a") {
+		t.Errorf("outer constant a reported as constant of the inner environment")
+	}
+
+	scope, ok := inner.Resolve("a")
+	if !ok || scope != outer || !scope.IsConst("a") {
+		t.Errorf("Resolve(a) did not return the outer environment")
+	}
+}
+
+func TestRangeLen(t *testing.T) {
+	tests := []struct {
+		r        *Range
+		expected int64
+	}{
+		{&Range{Start: 1, End: 5}, 4},
+		{&Range{Start: 1, End: 5, Inclusive: true}, 5},
+		{&Range{Start: 5, End: 5}, 0},
+		{&Range{Start: 5, End: 5, Inclusive: true}, 1},
+		{&Range{Start: 5, End: 1, Inclusive: true}, 0},
+		{&Range{Start: math.MaxInt64 - 1, End: math.MaxInt64, Inclusive: true}, 2},
+		{&Range{Start: math.MinInt64, End: math.MinInt64 + 2}, 2},
+		{&Range{Start: 0, End: math.MaxInt64}, math.MaxInt64},
+		{&Range{Start: 0, End: math.MaxInt64, Inclusive: true}, math.MaxInt64},
+		{&Range{Start: -1, End: math.MaxInt64 - 1, Inclusive: true}, math.MaxInt64},
+		{&Range{Start: -math.MaxInt64, End: math.MaxInt64}, math.MaxInt64},
+		{&Range{Start: math.MinInt64, End: math.MaxInt64, Inclusive: true}, math.MaxInt64},
+	}
+
+	for _, tt := range tests {
+		if got := tt.r.Len(); got != tt.expected {
+			t.Errorf("(%s).Len() wrong. want=%d, got=%d", tt.r.Inspect(), tt.expected, got)
+		}
+	}
+}
+
+func TestRangeContains(t *testing.T) {
+	tests := []struct {
+		r        *Range
+		n        int64
+		expected bool
+	}{
+		{&Range{Start: 1, End: 5}, 4, true},
+		{&Range{Start: 1, End: 5}, 5, false},
+		{&Range{Start: 1, End: 5, Inclusive: true}, 5, true},
+		{&Range{Start: 1, End: 5}, 0, false},
+		{&Range{Start: 0, End: math.MaxInt64, Inclusive: true}, math.MaxInt64, true},
+		{&Range{Start: 0, End: math.MaxInt64}, math.MaxInt64, false},
+		{&Range{Start: -math.MaxInt64, End: math.MaxInt64}, math.MaxInt64 - 1, true},
+		{&Range{Start: -math.MaxInt64, End: math.MaxInt64}, math.MinInt64, false},
+		{&Range{Start: math.MinInt64, End: math.MaxInt64, Inclusive: true}, math.MinInt64, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.r.Contains(tt.n); got != tt.expected {
+			t.Errorf("(%s).Contains(%d) wrong. want=%t, got=%t", tt.r.Inspect(), tt.n, tt.expected, got)
+		}
+	}
+}
+
+func TestRangeIndex(t *testing.T) {
+	tests := []struct {
+		r        *Range
+		i        int64
+		expected int64
+		ok       bool
+	}{
+		{&Range{Start: 1, End: 5}, 0, 1, true},
+		{&Range{Start: 1, End: 5}, 3, 4, true},
+		{&Range{Start: 1, End: 5}, 4, 0, false},
+		{&Range{Start: 1, End: 5}, -1, 4, true},
+		{&Range{Start: 1, End: 5, Inclusive: true}, -1, 5, true},
+		{&Range{Start: 1, End: 5}, -4, 1, true},
+		{&Range{Start: 1, End: 5}, -5, 0, false},
+		{&Range{Start: 5, End: 1}, -1, 0, false},
+		{&Range{Start: 1, End: math.MaxInt64}, math.MaxInt64, 0, false},
+		{&Range{Start: -math.MaxInt64, End: math.MaxInt64}, -1, math.MaxInt64 - 1, true},
+		{&Range{Start: -math.MaxInt64, End: math.MaxInt64}, math.MaxInt64, 0, true},
+		{&Range{Start: math.MinInt64, End: math.MaxInt64, Inclusive: true}, -1, math.MaxInt64, true},
+		{&Range{Start: math.MinInt64, End: -1}, math.MinInt64, 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := tt.r.Index(tt.i)
+		if ok != tt.ok || ok && got != tt.expected {
+			t.Errorf("(%s).Index(%d) wrong. want=%d, %t, got=%d, %t",
+				tt.r.Inspect(), tt.i, tt.expected, tt.ok, got, ok)
+		}
+	}
+}
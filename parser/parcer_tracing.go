@@ -2,31 +2,55 @@ package parser
 
 import (
 	"fmt"
+	"io"
 	"strings"
 )
 
-var traceLevel int = 0
-
 const traceIdentPlaceholder string = "\t"
 
-func identLevel() string {
-	return strings.Repeat(traceIdentPlaceholder, traceLevel-1)
+// tracer writes the BEGIN/END lines of a single parser's trace.
+type tracer struct {
+	out   io.Writer
+	level int
+}
+
+// WithTracing makes the parser write an indented BEGIN/END line to out
+// each time it enters or leaves a parse function.
+func WithTracing(out io.Writer) Option {
+	return func(p *Parser) {
+		p.tracer = &tracer{out: out}
+	}
 }
 
-func tracePrint(fs string) {
-	fmt.Printf("%s%s\n", identLevel(), fs)
+func (t *tracer) identLevel() string {
+	return strings.Repeat(traceIdentPlaceholder, t.level-1)
 }
 
-func incIdent() { traceLevel = traceLevel + 1 }
-func decIdent() { traceLevel = traceLevel - 1 }
+func (t *tracer) tracePrint(fs string) {
+	fmt.Fprintf(t.out, "%s%s\n", t.identLevel(), fs)
+}
 
-func trace(msg string) string {
-	incIdent()
-	tracePrint("BEGIN " + msg)
+func (t *tracer) incIdent() { t.level = t.level + 1 }
+func (t *tracer) decIdent() { t.level = t.level - 1 }
+
+// trace records entering the parse function msg. It is meant to be used
+// as `defer p.untrace(p.trace("parseExpression"))` and does nothing
+// unless tracing is enabled.
+func (p *Parser) trace(msg string) string {
+	if p.tracer == nil {
+		return msg
+	}
+
+	p.tracer.incIdent()
+	p.tracer.tracePrint("BEGIN " + msg)
 	return msg
 }
 
-func untrace(msg string) {
-	tracePrint("END " + msg)
-	decIdent()
+func (p *Parser) untrace(msg string) {
+	if p.tracer == nil {
+		return
+	}
+
+	p.tracer.tracePrint("END " + msg)
+	p.tracer.decIdent()
 }
@@ -1,7 +1,9 @@
 package parser
 
 import (
+	"bytes"
 	"fmt"
+	"sync"
 	"testing"
 
 	"github.com/Devashish08/InterPreter-Compiler/ast"
@@ -42,6 +44,38 @@ func TestLetStatements(t *testing.T) {
 	}
 }
 
+func TestConstStatements(t *testing.T) {
+	l := lexer.New(`const MAX = 10; let x = MAX;`)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 2 {
+		t.Fatalf("program.Statements does not contain 2 statements. got=%d",
+			len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.LetStatement)
+	if !ok {
+		t.Fatalf("stmt is not *ast.LetStatement. got=%T", program.Statements[0])
+	}
+
+	if !stmt.IsConst() {
+		t.Errorf("stmt.IsConst() is false for %q", stmt.String())
+	}
+
+	if stmt.String() != "const MAX = 10;" {
+		t.Errorf("stmt.String() wrong. got=%q", stmt.String())
+	}
+
+	testIdentifier(t, stmt.Name, "MAX")
+	testLiteralExpression(t, stmt.Value, 10)
+
+	if program.Statements[1].(*ast.LetStatement).IsConst() {
+		t.Errorf("let statement reported as const")
+	}
+}
+
 func TestReturnStatements(t *testing.T) {
 	tests := []struct {
 		input         string
@@ -211,6 +245,7 @@ func TestParsingInfixExpressions(t *testing.T) {
 		{"true == true", true, "==", true},
 		{"true != false", true, "!=", false},
 		{"false == false", false, "==", false},
+		{"x in xs", "x", "in", "xs"},
 	}
 
 	for _, tt := range infixTests {
@@ -350,6 +385,42 @@ func TestOperatorPrecedenceParsing(t *testing.T) {
 			"add(a * b[2], b[1], 2 * [1, 2][1])",
 			"add((a * (b[2])), (b[1]), (2 * ([1, 2][1])))",
 		},
+		{
+			"x = y = 5",
+			"(x = (y = 5))",
+		},
+		{
+			"x += a * b",
+			"(x += (a * b))",
+		},
+		{
+			"a.b.c",
+			"a.b.c",
+		},
+		{
+			"-a.b",
+			"(-a.b)",
+		},
+		{
+			"self.n = a.b(c)[1]",
+			"(self.n = (a.b(c)[1]))",
+		},
+		{
+			"1..n + 1",
+			"(1..(n + 1))",
+		},
+		{
+			"0..=len(xs) - 1",
+			"(0..=(len(xs) - 1))",
+		},
+		{
+			"x in 1..10 == true",
+			"((x in (1..10)) == true)",
+		},
+		{
+			"(1..5)[2]",
+			"((1..5)[2])",
+		},
 	}
 
 	for _, tt := range tests {
@@ -557,6 +628,53 @@ func TestFunctionLiteralParsing(t *testing.T) {
 	testInfixExpression(t, bodyStmt.Expression, "x", "+", "y")
 }
 
+func TestMacroLiteralParsing(t *testing.T) {
+	input := `macro(x, y) { x + y; }`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain %d statements. got=%d\n",
+			1, len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("statement is not ast.ExpressionStatement. got=%T",
+			program.Statements[0])
+	}
+
+	macro, ok := stmt.Expression.(*ast.MacroLiteral)
+	if !ok {
+		t.Fatalf("stmt.Expression is not ast.MacroLiteral. got=%T",
+			stmt.Expression)
+	}
+
+	if len(macro.Parameters) != 2 {
+		t.Fatalf("macro literal parameters wrong. want 2, got=%d\n",
+			len(macro.Parameters))
+	}
+
+	testLiteralExpression(t, macro.Parameters[0], "x")
+	testLiteralExpression(t, macro.Parameters[1], "y")
+
+	if len(macro.Body.Statements) != 1 {
+		t.Fatalf("macro.Body.Statements has not 1 statements. got=%d\n",
+			len(macro.Body.Statements))
+	}
+
+	bodyStmt, ok := macro.Body.Statements[0].(*ast.ExpressionStatement)
+	if !ok {
+		t.Fatalf("macro body stmt is not ast.ExpressionStatement. got=%T",
+			macro.Body.Statements[0])
+	}
+
+	testInfixExpression(t, bodyStmt.Expression, "x", "+", "y")
+}
+
 func TestFunctionParameterParsing(t *testing.T) {
 	tests := []struct {
 		input          string
@@ -587,6 +705,54 @@ func TestFunctionParameterParsing(t *testing.T) {
 	}
 }
 
+func TestTypeAnnotationParsing(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let n: int = 5;", "let n: int = 5;"},
+		{"const names: [string] = [];", "const names: [string] = [];"},
+		{"fn(x: int, names: [string]) -> bool { true }", "fn(x: int, names: [string]) -> bool true"},
+		{"fn(x, h: {string: [int]}) { x }", "fn(x, h: {string: [int]}) x"},
+		{"fn(f: fn) -> Point { f }", "fn(f: fn) -> Point f"},
+		{"fn(x) -> int { x }", "fn(x) -> int x"},
+		{"class P { add(x: int, y) -> int { x } }", "class P { add(x: int, y) -> int x }"},
+		{"class P { init(x) { x } }", "class P { init(x) x }"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
+func TestTypeAnnotationParsingErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"let n: = 5;", "expected a type, got ="},
+		{"fn(x: [int) { x }", "expected next token to be ], got ) instead"},
+		{"fn(x) -> 5 { x }", "expected a type, got INT"},
+	}
+
+	for _, tt := range tests {
+		p := New(lexer.New(tt.input))
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 || errors[0] != tt.expected {
+			t.Errorf("input %q: expected first error %q, got=%v", tt.input, tt.expected, errors)
+		}
+	}
+}
+
 func TestCallExpressionParsing(t *testing.T) {
 	input := "add(1, 2 * 3, 4 + 5);"
 
@@ -763,6 +929,37 @@ func TestParsingIndexExpressions(t *testing.T) {
 	}
 }
 
+func TestParsingSliceExpressions(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"arr[1:3]", "(arr[1:3])"},
+		{"arr[:-1]", "(arr[:(-1)])"},
+		{"arr[::2]", "(arr[::2])"},
+		{"arr[1:]", "(arr[1:])"},
+		{"arr[:]", "(arr[:])"},
+		{"arr[a + 1:b * 2:-1]", "(arr[(a + 1):(b * 2):(-1)])"},
+		{"str[0:5][1]", "((str[0:5])[1])"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		if _, ok := stmt.Expression.(*ast.SliceExpression); !ok && tt.input != "str[0:5][1]" {
+			t.Fatalf("exp not *ast.SliceExpression. got=%T", stmt.Expression)
+		}
+
+		if program.String() != tt.expected {
+			t.Errorf("expected=%q, got=%q", tt.expected, program.String())
+		}
+	}
+}
+
 func TestParsingEmptyHashLiteral(t *testing.T) {
 	input := "{}"
 
@@ -806,7 +1003,8 @@ func TestParsingHashLiteralsStringKeys(t *testing.T) {
 		t.Errorf("hash.Pairs has wrong length. got=%d", len(hash.Pairs))
 	}
 
-	for key, value := range hash.Pairs {
+	for _, pair := range hash.Pairs {
+		key, value := pair.Key, pair.Value
 		literal, ok := key.(*ast.StringLiteral)
 		if !ok {
 			t.Errorf("key is not ast.StringLiteral. got=%T", key)
@@ -841,7 +1039,8 @@ func TestParsingHashLiteralsBooleanKeys(t *testing.T) {
 		t.Errorf("hash.Pairs has wrong length. got=%d", len(hash.Pairs))
 	}
 
-	for key, value := range hash.Pairs {
+	for _, pair := range hash.Pairs {
+		key, value := pair.Key, pair.Value
 		boolean, ok := key.(*ast.Boolean)
 		if !ok {
 			t.Errorf("key is not ast.BooleanLiteral. got=%T", key)
@@ -877,7 +1076,8 @@ func TestParsingHashLiteralsIntegerKeys(t *testing.T) {
 		t.Errorf("hash.Pairs has wrong length. got=%d", len(hash.Pairs))
 	}
 
-	for key, value := range hash.Pairs {
+	for _, pair := range hash.Pairs {
+		key, value := pair.Key, pair.Value
 		integer, ok := key.(*ast.IntegerLiteral)
 		if !ok {
 			t.Errorf("key is not ast.IntegerLiteral. got=%T", key)
@@ -890,6 +1090,37 @@ func TestParsingHashLiteralsIntegerKeys(t *testing.T) {
 	}
 }
 
+func TestParsingHashLiteralsKeepSourceOrder(t *testing.T) {
+	input := `{"zebra": 1, "apple": 2, 3: "three", true: 4}`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt := program.Statements[0].(*ast.ExpressionStatement)
+	hash, ok := stmt.Expression.(*ast.HashLiteral)
+	if !ok {
+		t.Fatalf("exp is not ast.HashLiteral. got=%T", stmt.Expression)
+	}
+
+	expected := []string{"zebra", "apple", "3", "true"}
+	if len(hash.Pairs) != len(expected) {
+		t.Fatalf("hash.Pairs has wrong length. got=%d", len(hash.Pairs))
+	}
+
+	for i, key := range expected {
+		if hash.Pairs[i].Key.String() != key {
+			t.Errorf("hash.Pairs[%d] has wrong key. want=%q, got=%q",
+				i, key, hash.Pairs[i].Key.String())
+		}
+	}
+
+	if hash.String() != "{zebra:1, apple:2, 3:three, true:4}" {
+		t.Errorf("hash.String() wrong. got=%q", hash.String())
+	}
+}
+
 func TestParsingHashLiteralsWithExpressions(t *testing.T) {
 	input := `{"one": 0 + 1, "two": 10 - 8, "three": 15 / 5}`
 
@@ -920,7 +1151,8 @@ func TestParsingHashLiteralsWithExpressions(t *testing.T) {
 		},
 	}
 
-	for key, value := range hash.Pairs {
+	for _, pair := range hash.Pairs {
+		key, value := pair.Key, pair.Value
 		literal, ok := key.(*ast.StringLiteral)
 		if !ok {
 			t.Errorf("key is not ast.StringLiteral. got=%T", key)
@@ -937,6 +1169,733 @@ func TestParsingHashLiteralsWithExpressions(t *testing.T) {
 	}
 }
 
+func TestClassStatementParsing(t *testing.T) {
+	input := `class Counter extends Base {
+  init(n) { self.n = n; }
+  inc() { self.n += 1; }
+}`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statements. got=%d",
+			len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.ClassStatement)
+	if !ok {
+		t.Fatalf("stmt is not *ast.ClassStatement. got=%T", program.Statements[0])
+	}
+
+	if stmt.Name.Value != "Counter" {
+		t.Errorf("stmt.Name.Value not 'Counter'. got=%s", stmt.Name.Value)
+	}
+
+	if stmt.Superclass == nil || stmt.Superclass.Value != "Base" {
+		t.Errorf("stmt.Superclass not 'Base'. got=%v", stmt.Superclass)
+	}
+
+	expected := []struct {
+		name   string
+		params []string
+	}{
+		{"init", []string{"n"}},
+		{"inc", []string{}},
+	}
+
+	if len(stmt.Methods) != len(expected) {
+		t.Fatalf("wrong number of methods. want=%d, got=%d",
+			len(expected), len(stmt.Methods))
+	}
+
+	for i, method := range expected {
+		if stmt.Methods[i].Name != method.name {
+			t.Errorf("methods[%d] has wrong name. want=%s, got=%s",
+				i, method.name, stmt.Methods[i].Name)
+		}
+
+		if len(stmt.Methods[i].Parameters) != len(method.params) {
+			t.Errorf("methods[%d] has wrong number of parameters. want=%d, got=%d",
+				i, len(method.params), len(stmt.Methods[i].Parameters))
+			continue
+		}
+
+		for j, param := range method.params {
+			testLiteralExpression(t, stmt.Methods[i].Parameters[j], param)
+		}
+	}
+}
+
+func TestEnumStatementParsing(t *testing.T) {
+	input := `enum Shape { Circle(r), Rect(w, h), Empty }`
+
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	if len(program.Statements) != 1 {
+		t.Fatalf("program.Statements does not contain 1 statements. got=%d",
+			len(program.Statements))
+	}
+
+	stmt, ok := program.Statements[0].(*ast.EnumStatement)
+	if !ok {
+		t.Fatalf("stmt is not *ast.EnumStatement. got=%T", program.Statements[0])
+	}
+
+	if stmt.Name.Value != "Shape" {
+		t.Errorf("stmt.Name.Value not 'Shape'. got=%s", stmt.Name.Value)
+	}
+
+	expected := []struct {
+		name   string
+		fields []string
+	}{
+		{"Circle", []string{"r"}},
+		{"Rect", []string{"w", "h"}},
+		{"Empty", []string{}},
+	}
+
+	if len(stmt.Variants) != len(expected) {
+		t.Fatalf("wrong number of variants. want=%d, got=%d",
+			len(expected), len(stmt.Variants))
+	}
+
+	for i, variant := range expected {
+		testIdentifier(t, stmt.Variants[i].Name, variant.name)
+
+		if len(stmt.Variants[i].Fields) != len(variant.fields) {
+			t.Errorf("variants[%d] has wrong number of fields. want=%d, got=%d",
+				i, len(variant.fields), len(stmt.Variants[i].Fields))
+			continue
+		}
+
+		for j, field := range variant.fields {
+			testIdentifier(t, stmt.Variants[i].Fields[j], field)
+		}
+	}
+
+	if stmt.String() != "enum Shape { Circle(r), Rect(w, h), Empty }" {
+		t.Errorf("stmt.String() wrong. got=%q", stmt.String())
+	}
+}
+
+func TestDuplicateEnumVariant(t *testing.T) {
+	l := lexer.New("enum Color { Red, Red }")
+	p := New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) == 0 {
+		t.Fatalf("expected parser errors. got none")
+	}
+
+	if errors[0] != "duplicate variant Red in enum Color" {
+		t.Errorf("wrong error message. got=%q", errors[0])
+	}
+}
+
+func TestTryStatementParsing(t *testing.T) {
+	tests := []struct {
+		input      string
+		catchParam string
+		hasCatch   bool
+		hasFinally bool
+	}{
+		{"try { x } catch (e) { y }", "e", true, false},
+		{"try { x } catch { y }", "", true, false},
+		{"try { x } finally { z }", "", false, true},
+		{"try { x } catch (err) { y } finally { z };", "err", true, true},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statements. got=%d",
+				len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.TryStatement)
+		if !ok {
+			t.Fatalf("stmt is not *ast.TryStatement. got=%T", program.Statements[0])
+		}
+
+		if len(stmt.Block.Statements) != 1 {
+			t.Errorf("try block has wrong number of statements. got=%d",
+				len(stmt.Block.Statements))
+		}
+
+		if (stmt.Catch != nil) != tt.hasCatch {
+			t.Errorf("catch block presence wrong. want=%t", tt.hasCatch)
+		}
+
+		if (stmt.Finally != nil) != tt.hasFinally {
+			t.Errorf("finally block presence wrong. want=%t", tt.hasFinally)
+		}
+
+		if tt.catchParam == "" {
+			if stmt.CatchParam != nil {
+				t.Errorf("stmt.CatchParam not nil. got=%s", stmt.CatchParam)
+			}
+		} else {
+			testIdentifier(t, stmt.CatchParam, tt.catchParam)
+		}
+	}
+}
+
+func TestTryWithoutHandlers(t *testing.T) {
+	l := lexer.New("try { x }")
+	p := New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) == 0 {
+		t.Fatalf("expected parser errors. got none")
+	}
+
+	if errors[0] != "try statement requires catch or finally" {
+		t.Errorf("wrong error message. got=%q", errors[0])
+	}
+}
+
+func TestThrowStatementParsing(t *testing.T) {
+	l := lexer.New(`throw "boom";`)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt, ok := program.Statements[0].(*ast.ThrowStatement)
+	if !ok {
+		t.Fatalf("stmt is not *ast.ThrowStatement. got=%T", program.Statements[0])
+	}
+
+	if stmt.String() != "throw boom;" {
+		t.Errorf("stmt.String() wrong. got=%q", stmt.String())
+	}
+}
+
+func TestDeferStatementParsing(t *testing.T) {
+	l := lexer.New(`defer close(file);`)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt, ok := program.Statements[0].(*ast.DeferStatement)
+	if !ok {
+		t.Fatalf("stmt is not *ast.DeferStatement. got=%T", program.Statements[0])
+	}
+
+	call, ok := stmt.Expression.(*ast.CallExpression)
+	if !ok {
+		t.Fatalf("stmt.Expression is not *ast.CallExpression. got=%T", stmt.Expression)
+	}
+
+	testIdentifier(t, call.Function, "close")
+}
+
+func TestForStatementParsing(t *testing.T) {
+	tests := []struct {
+		input            string
+		expectedKey      string
+		expectedValue    string
+		expectedIterable string
+	}{
+		{"for x in xs { puts(x); }", "", "x", "xs"},
+		{"for (k, v) in h { puts(k, v); }", "k", "v", "h"},
+		{"for i in 0..=n { i; };", "", "i", "(0..=n)"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statement. got=%d",
+				len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ForStatement)
+		if !ok {
+			t.Fatalf("stmt is not *ast.ForStatement. got=%T", program.Statements[0])
+		}
+
+		if tt.expectedKey == "" {
+			if stmt.Key != nil {
+				t.Errorf("stmt.Key is not nil. got=%s", stmt.Key)
+			}
+		} else if !testIdentifier(t, stmt.Key, tt.expectedKey) {
+			return
+		}
+
+		if !testIdentifier(t, stmt.Value, tt.expectedValue) {
+			return
+		}
+
+		if stmt.Iterable.String() != tt.expectedIterable {
+			t.Errorf("stmt.Iterable wrong. want=%q, got=%q",
+				tt.expectedIterable, stmt.Iterable.String())
+		}
+
+		if len(stmt.Body.Statements) != 1 {
+			t.Errorf("body does not contain 1 statement. got=%d",
+				len(stmt.Body.Statements))
+		}
+	}
+}
+
+func TestInvalidForStatements(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"for x xs { x }", "expected next token to be IN, got IDENT instead"},
+		{"for (k) in h { k }", "expected next token to be ,, got ) instead"},
+		{"for x in xs x", "expected next token to be {, got IDENT instead"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("expected parser errors for %q", tt.input)
+			continue
+		}
+
+		if errors[0] != tt.expectedError {
+			t.Errorf("wrong error message. want=%q, got=%q", tt.expectedError, errors[0])
+		}
+	}
+}
+
+func TestRangeExpressionParsing(t *testing.T) {
+	tests := []struct {
+		input     string
+		inclusive bool
+	}{
+		{"1..5", false},
+		{"1..=5", true},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		rng, ok := stmt.Expression.(*ast.RangeExpression)
+		if !ok {
+			t.Fatalf("exp not *ast.RangeExpression. got=%T", stmt.Expression)
+		}
+
+		if !testIntegerLiteral(t, rng.Start, 1) || !testIntegerLiteral(t, rng.End, 5) {
+			return
+		}
+
+		if rng.Inclusive != tt.inclusive {
+			t.Errorf("rng.Inclusive wrong. want=%t, got=%t", tt.inclusive, rng.Inclusive)
+		}
+	}
+}
+
+func TestParsingArrayComprehensions(t *testing.T) {
+	tests := []struct {
+		input             string
+		expectedKey       string
+		expectedValue     string
+		expectedCondition string
+		expectedString    string
+	}{
+		{"[x * 2 for x in xs if x > 0]", "", "x", "(x > 0)", "[(x * 2) for x in xs if (x > 0)]"},
+		{"[x for x in 1..10]", "", "x", "", "[x for x in (1..10)]"},
+		{"[i + v for (i, v) in xs]", "i", "v", "", "[(i + v) for (i, v) in xs]"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		comp, ok := stmt.Expression.(*ast.ArrayComprehension)
+		if !ok {
+			t.Fatalf("exp not *ast.ArrayComprehension. got=%T", stmt.Expression)
+		}
+
+		testComprehensionClause(t, comp.Clause, tt.expectedKey, tt.expectedValue, tt.expectedCondition)
+
+		if comp.String() != tt.expectedString {
+			t.Errorf("comp.String() wrong. want=%q, got=%q", tt.expectedString, comp.String())
+		}
+	}
+}
+
+func TestParsingHashComprehensions(t *testing.T) {
+	tests := []struct {
+		input             string
+		expectedKey       string
+		expectedValue     string
+		expectedCondition string
+		expectedString    string
+	}{
+		{"{k: v for (k, v) in h}", "k", "v", "", "{k:v for (k, v) in h}"},
+		{`{x: x * x for x in xs if x != 2}`, "", "x", "(x != 2)", "{x:(x * x) for x in xs if (x != 2)}"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		stmt := program.Statements[0].(*ast.ExpressionStatement)
+		comp, ok := stmt.Expression.(*ast.HashComprehension)
+		if !ok {
+			t.Fatalf("exp not *ast.HashComprehension. got=%T", stmt.Expression)
+		}
+
+		testComprehensionClause(t, comp.Clause, tt.expectedKey, tt.expectedValue, tt.expectedCondition)
+
+		if comp.String() != tt.expectedString {
+			t.Errorf("comp.String() wrong. want=%q, got=%q", tt.expectedString, comp.String())
+		}
+	}
+}
+
+func TestInvalidComprehensions(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"[x for x xs]", "expected next token to be IN, got IDENT instead"},
+		{"[x for x in xs, 1]", "expected next token to be ], got , instead"},
+		{"{k: v for (k, v) in h, 1: 2}", "expected next token to be }, got , instead"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("expected parser errors for %q", tt.input)
+			continue
+		}
+
+		if errors[0] != tt.expectedError {
+			t.Errorf("wrong error message. want=%q, got=%q", tt.expectedError, errors[0])
+		}
+	}
+}
+
+func testComprehensionClause(
+	t *testing.T,
+	clause *ast.ComprehensionClause,
+	key, value, condition string,
+) {
+	t.Helper()
+
+	if key == "" {
+		if clause.Key != nil {
+			t.Errorf("clause.Key is not nil. got=%s", clause.Key)
+		}
+	} else {
+		testIdentifier(t, clause.Key, key)
+	}
+
+	testIdentifier(t, clause.Value, value)
+
+	if condition == "" {
+		if clause.Condition != nil {
+			t.Errorf("clause.Condition is not nil. got=%s", clause.Condition)
+		}
+	} else if clause.Condition == nil || clause.Condition.String() != condition {
+		t.Errorf("clause.Condition wrong. want=%q, got=%v", condition, clause.Condition)
+	}
+}
+
+func TestYieldMarksGenerators(t *testing.T) {
+	input := `
+let gen = fn(n) { yield n; let inner = fn() { n }; };
+let plain = fn() { let g = fn() { yield 1; }; g };
+class C { items() { yield 1; } size() { 0 } }
+`
+	l := lexer.New(input)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	gen := program.Statements[0].(*ast.LetStatement).Value.(*ast.FunctionLiteral)
+	if !gen.Generator {
+		t.Errorf("gen is not marked as generator")
+	}
+
+	yield, ok := gen.Body.Statements[0].(*ast.YieldStatement)
+	if !ok {
+		t.Fatalf("gen.Body.Statements[0] is not *ast.YieldStatement. got=%T",
+			gen.Body.Statements[0])
+	}
+	testIdentifier(t, yield.Value, "n")
+
+	inner := gen.Body.Statements[1].(*ast.LetStatement).Value.(*ast.FunctionLiteral)
+	if inner.Generator {
+		t.Errorf("inner function without yield is marked as generator")
+	}
+
+	plain := program.Statements[1].(*ast.LetStatement).Value.(*ast.FunctionLiteral)
+	if plain.Generator {
+		t.Errorf("function with a nested generator is marked as generator")
+	}
+
+	nested := plain.Body.Statements[0].(*ast.LetStatement).Value.(*ast.FunctionLiteral)
+	if !nested.Generator {
+		t.Errorf("nested function with yield is not marked as generator")
+	}
+
+	class := program.Statements[2].(*ast.ClassStatement)
+	if !class.Methods[0].Generator || class.Methods[1].Generator {
+		t.Errorf("wrong generator flags on methods. got=%t, %t",
+			class.Methods[0].Generator, class.Methods[1].Generator)
+	}
+}
+
+func TestYieldOutsideFunction(t *testing.T) {
+	tests := []string{
+		"yield 1;",
+		"let m = macro(x) { yield x; };",
+	}
+
+	for _, input := range tests {
+		l := lexer.New(input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("expected parser errors for %q", input)
+			continue
+		}
+
+		if errors[0] != "yield outside of function" {
+			t.Errorf("wrong error message. got=%q", errors[0])
+		}
+	}
+}
+
+func TestImportStatementParsing(t *testing.T) {
+	l := lexer.New(`import "lib/strings" as s;`)
+	p := New(l)
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	stmt, ok := program.Statements[0].(*ast.ImportStatement)
+	if !ok {
+		t.Fatalf("stmt is not *ast.ImportStatement. got=%T", program.Statements[0])
+	}
+
+	if stmt.Path.Value != "lib/strings" {
+		t.Errorf("stmt.Path.Value not %q. got=%q", "lib/strings", stmt.Path.Value)
+	}
+
+	testIdentifier(t, stmt.Alias, "s")
+
+	if stmt.String() != `import "lib/strings" as s;` {
+		t.Errorf("stmt.String() wrong. got=%q", stmt.String())
+	}
+}
+
+func TestExportStatementParsing(t *testing.T) {
+	tests := []struct {
+		input        string
+		expectedType string
+	}{
+		{"export let x = 1;", "*ast.LetStatement"},
+		{"export const X = 1;", "*ast.LetStatement"},
+		{"export class C { }", "*ast.ClassStatement"},
+		{"export enum E { A }", "*ast.EnumStatement"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		program := p.ParseProgram()
+		checkParserErrors(t, p)
+
+		if len(program.Statements) != 1 {
+			t.Fatalf("program.Statements does not contain 1 statement. got=%d",
+				len(program.Statements))
+		}
+
+		stmt, ok := program.Statements[0].(*ast.ExportStatement)
+		if !ok {
+			t.Fatalf("stmt is not *ast.ExportStatement. got=%T", program.Statements[0])
+		}
+
+		if got := fmt.Sprintf("%T", stmt.Statement); got != tt.expectedType {
+			t.Errorf("stmt.Statement has wrong type. want=%s, got=%s", tt.expectedType, got)
+		}
+	}
+}
+
+func TestInvalidImportExport(t *testing.T) {
+	tests := []struct {
+		input         string
+		expectedError string
+	}{
+		{"import lib as l;", "expected next token to be STRING, got IDENT instead"},
+		{`import "lib";`, "expected next token to be AS, got ; instead"},
+		{`import "lib" as "l";`, "expected next token to be IDENT, got STRING instead"},
+		{"export x;", "export must be followed by let, const, class or enum, got IDENT"},
+		{"export let = 1;", "expected next token to be IDENT, got = instead"},
+	}
+
+	for _, tt := range tests {
+		l := lexer.New(tt.input)
+		p := New(l)
+		p.ParseProgram()
+
+		errors := p.Errors()
+		if len(errors) == 0 {
+			t.Errorf("expected parser errors for %q", tt.input)
+			continue
+		}
+
+		if errors[0] != tt.expectedError {
+			t.Errorf("wrong error message. want=%q, got=%q", tt.expectedError, errors[0])
+		}
+	}
+}
+
+func TestDocComments(t *testing.T) {
+	input := `
+/// Adds two numbers.
+///
+/// Second paragraph.
+let add = fn(a, b) { a + b };
+// not documentation
+let x = 1;
+/// Exported.
+export let y = 2;
+/// Dropped: nothing to attach to.
+x;
+let z = 3;
+`
+
+	p := New(lexer.New(input))
+	program := p.ParseProgram()
+	checkParserErrors(t, p)
+
+	tests := []struct {
+		index int
+		doc   string
+	}{
+		{0, "Adds two numbers.\n\nSecond paragraph."},
+		{1, ""},
+		{2, "Exported."},
+		{4, ""},
+	}
+
+	for _, tt := range tests {
+		stmt := program.Statements[tt.index]
+		if export, ok := stmt.(*ast.ExportStatement); ok {
+			stmt = export.Statement
+		}
+
+		let, ok := stmt.(*ast.LetStatement)
+		if !ok {
+			t.Fatalf("statement %d is not *ast.LetStatement. got=%T", tt.index, stmt)
+		}
+		if let.Doc != tt.doc {
+			t.Errorf("statement %d: wrong doc. expected=%q, got=%q", tt.index, tt.doc, let.Doc)
+		}
+	}
+}
+
+func TestTracing(t *testing.T) {
+	var out bytes.Buffer
+
+	p := New(lexer.New("1 + 2;"), WithTracing(&out))
+	p.ParseProgram()
+	checkParserErrors(t, p)
+
+	expected := `BEGIN parseStatement
+	BEGIN parseExpressionStatement
+		BEGIN parseExpression
+			BEGIN parseIntegerLiteral
+			END parseIntegerLiteral
+			BEGIN parseInfixExpression
+				BEGIN parseExpression
+					BEGIN parseIntegerLiteral
+					END parseIntegerLiteral
+				END parseExpression
+			END parseInfixExpression
+		END parseExpression
+	END parseExpressionStatement
+END parseStatement
+`
+
+	if out.String() != expected {
+		t.Errorf("wrong trace. want=\n%s\ngot=\n%s", expected, out.String())
+	}
+}
+
+func TestTracingIsPerParser(t *testing.T) {
+	const parsers = 8
+
+	outputs := make([]bytes.Buffer, parsers)
+	var wg sync.WaitGroup
+
+	for i := 0; i < parsers; i++ {
+		wg.Add(1)
+		go func(out *bytes.Buffer) {
+			defer wg.Done()
+			New(lexer.New("let x = fn(a) { a * (1 + 2) };"), WithTracing(out)).ParseProgram()
+		}(&outputs[i])
+	}
+	wg.Wait()
+
+	for i := 1; i < parsers; i++ {
+		if outputs[i].String() != outputs[0].String() {
+			t.Fatalf("trace %d differs from trace 0:\n%s\n---\n%s",
+				i, outputs[i].String(), outputs[0].String())
+		}
+	}
+
+	if !bytes.HasSuffix(outputs[0].Bytes(), []byte("END parseStatement\n")) {
+		t.Errorf("trace does not return to the top level:\n%s", outputs[0].String())
+	}
+}
+
+func TestInvalidAssignmentTarget(t *testing.T) {
+	l := lexer.New("5 = x;")
+	p := New(l)
+	p.ParseProgram()
+
+	errors := p.Errors()
+	if len(errors) != 1 {
+		t.Fatalf("expected 1 parser error. got=%d (%v)", len(errors), errors)
+	}
+
+	if errors[0] != "invalid assignment target 5" {
+		t.Errorf("wrong error message. got=%q", errors[0])
+	}
+}
+
 func testLetStatement(t *testing.T, s ast.Statement, name string) bool {
 	if s.TokenLiteral() != "let" {
 		t.Errorf("s.TokenLiteral not 'let'. got=%q", s.TokenLiteral())
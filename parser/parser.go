@@ -20,6 +20,8 @@ Key components:
   - parseCallExpression: Handles function calls
   - parseIfExpression: Handles if/else statements
   - parseFunctionLiteral: Handles function definitions
+  - parseMacroLiteral: Handles macro definitions
+  - WithTracing: Option for New that traces parse functions to an io.Writer
 
 Error handling includes tracking of parsing errors and providing detailed error
 messages to help users identify syntax issues in their code.
@@ -29,6 +31,7 @@ package parser
 import (
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/Devashish08/InterPreter-Compiler/ast"
 	"github.com/Devashish08/InterPreter-Compiler/lexer"
@@ -38,8 +41,10 @@ import (
 const (
 	_ int = iota
 	LOWEST
+	ASSIGN      // x = y or x += y
 	EQUALS      // ==
-	LESSGREATER // > or <
+	LESSGREATER // > or < or in
+	RANGE       // a..b
 	SUM         // +
 	PRODUCT     // *
 	PREFIX      // -X or !X
@@ -48,16 +53,25 @@ const (
 )
 
 var precedences = map[token.TokenType]int{
-	token.EQ:       EQUALS,
-	token.NOT_EQ:   EQUALS,
-	token.LT:       LESSGREATER,
-	token.GT:       LESSGREATER,
-	token.PLUS:     SUM,
-	token.MINUS:    SUM,
-	token.SLASH:    PRODUCT,
-	token.ASTERISK: PRODUCT,
-	token.LPAREN:   CALL,
-	token.LBRACKET: INDEX,
+	token.ASSIGN:          ASSIGN,
+	token.PLUS_ASSIGN:     ASSIGN,
+	token.MINUS_ASSIGN:    ASSIGN,
+	token.ASTERISK_ASSIGN: ASSIGN,
+	token.SLASH_ASSIGN:    ASSIGN,
+	token.EQ:              EQUALS,
+	token.NOT_EQ:          EQUALS,
+	token.LT:              LESSGREATER,
+	token.GT:              LESSGREATER,
+	token.IN:              LESSGREATER,
+	token.DOTDOT:          RANGE,
+	token.DOTDOT_EQ:       RANGE,
+	token.PLUS:            SUM,
+	token.MINUS:           SUM,
+	token.SLASH:           PRODUCT,
+	token.ASTERISK:        PRODUCT,
+	token.LPAREN:          CALL,
+	token.LBRACKET:        INDEX,
+	token.DOT:             INDEX,
 }
 
 type (
@@ -72,16 +86,35 @@ type Parser struct {
 	curToken  token.Token
 	peekToken token.Token
 
+	// curDoc and peekDoc hold the text of the /// doc comments directly
+	// before curToken and peekToken.
+	curDoc  string
+	peekDoc string
+
 	prefixParseFns map[token.TokenType]prefixParseFn
 	infixParseFns  map[token.TokenType]infixParseFn
+
+	tracer *tracer // nil unless tracing is enabled
+
+	// functions holds the function literals enclosing the current token,
+	// innermost last, so that yield can mark its function as a generator.
+	// Macro bodies push nil.
+	functions []*ast.FunctionLiteral
 }
 
-func New(l *lexer.Lexer) *Parser {
+// Option configures optional behaviour of a Parser.
+type Option func(*Parser)
+
+func New(l *lexer.Lexer, opts ...Option) *Parser {
 	p := &Parser{
 		l:      l,
 		errors: []string{},
 	}
 
+	for _, opt := range opts {
+		opt(p)
+	}
+
 	p.prefixParseFns = make(map[token.TokenType]prefixParseFn)
 	p.registerPrefix(token.IDENT, p.parseIdentifier)
 	p.registerPrefix(token.INT, p.parseIntegerLiteral)
@@ -95,6 +128,7 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral)
 	p.registerPrefix(token.LBRACKET, p.parseArrayLiteral)
 	p.registerPrefix(token.LBRACE, p.parseHashLiteral)
+	p.registerPrefix(token.MACRO, p.parseMacroLiteral)
 
 	p.infixParseFns = make(map[token.TokenType]infixParseFn)
 	p.registerInfix(token.PLUS, p.parseInfixExpression)
@@ -105,8 +139,17 @@ func New(l *lexer.Lexer) *Parser {
 	p.registerInfix(token.NOT_EQ, p.parseInfixExpression)
 	p.registerInfix(token.LT, p.parseInfixExpression)
 	p.registerInfix(token.GT, p.parseInfixExpression)
+	p.registerInfix(token.IN, p.parseInfixExpression)
+	p.registerInfix(token.DOTDOT, p.parseRangeExpression)
+	p.registerInfix(token.DOTDOT_EQ, p.parseRangeExpression)
 	p.registerInfix(token.LPAREN, p.parseCallExpression)
 	p.registerInfix(token.LBRACKET, p.parseIndexExpression)
+	p.registerInfix(token.DOT, p.parseMemberExpression)
+	p.registerInfix(token.ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.PLUS_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.MINUS_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.ASTERISK_ASSIGN, p.parseAssignExpression)
+	p.registerInfix(token.SLASH_ASSIGN, p.parseAssignExpression)
 
 	// Read two tokens, so curToken and peekToken are both set
 	p.nextToken()
@@ -117,7 +160,31 @@ func New(l *lexer.Lexer) *Parser {
 
 func (p *Parser) nextToken() {
 	p.curToken = p.peekToken
-	p.peekToken = p.l.NextToken()
+	p.curDoc = p.peekDoc
+	p.peekToken, p.peekDoc = p.readToken()
+}
+
+// readToken returns the next token from the lexer, skipping comments. The
+// text of the doc comments before the token is returned with it, one line
+// per comment.
+func (p *Parser) readToken() (token.Token, string) {
+	doc := []string{}
+
+	for {
+		tok := p.l.NextToken()
+		switch tok.Type {
+		case token.COMMENT:
+		case token.DOC_COMMENT:
+			doc = append(doc, docText(tok.Literal))
+		default:
+			return tok, strings.Join(doc, "\n")
+		}
+	}
+}
+
+// docText strips the leading /// and the space after it from a doc comment.
+func docText(comment string) string {
+	return strings.TrimPrefix(strings.TrimPrefix(comment, "///"), " ")
 }
 
 func (p *Parser) curTokenIs(t token.TokenType) bool {
@@ -169,18 +236,40 @@ func (p *Parser) ParseProgram() *ast.Program {
 }
 
 func (p *Parser) parseStatement() ast.Statement {
+	defer p.untrace(p.trace("parseStatement"))
+
 	switch p.curToken.Type {
-	case token.LET:
+	case token.LET, token.CONST:
 		return p.parseLetStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
+	case token.CLASS:
+		return p.parseClassStatement()
+	case token.ENUM:
+		return p.parseEnumStatement()
+	case token.TRY:
+		return p.parseTryStatement()
+	case token.THROW:
+		return p.parseThrowStatement()
+	case token.DEFER:
+		return p.parseDeferStatement()
+	case token.FOR:
+		return p.parseForStatement()
+	case token.YIELD:
+		return p.parseYieldStatement()
+	case token.IMPORT:
+		return p.parseImportStatement()
+	case token.EXPORT:
+		return p.parseExportStatement()
 	default:
 		return p.parseExpressionStatement()
 	}
 }
 
 func (p *Parser) parseLetStatement() *ast.LetStatement {
-	stmt := &ast.LetStatement{Token: p.curToken}
+	defer p.untrace(p.trace("parseLetStatement"))
+
+	stmt := &ast.LetStatement{Token: p.curToken, Doc: p.curDoc}
 
 	if !p.expectPeek(token.IDENT) {
 		return nil
@@ -188,6 +277,16 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 
 	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 
+	if p.peekTokenIs(token.COLON) {
+		p.nextToken()
+		p.nextToken()
+
+		stmt.Type = p.parseTypeAnnotation()
+		if stmt.Type == nil {
+			return nil
+		}
+	}
+
 	if !p.expectPeek(token.ASSIGN) {
 		return nil
 	}
@@ -204,6 +303,8 @@ func (p *Parser) parseLetStatement() *ast.LetStatement {
 }
 
 func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
+	defer p.untrace(p.trace("parseReturnStatement"))
+
 	stmt := &ast.ReturnStatement{Token: p.curToken}
 
 	p.nextToken()
@@ -217,7 +318,356 @@ func (p *Parser) parseReturnStatement() *ast.ReturnStatement {
 	return stmt
 }
 
+func (p *Parser) parseClassStatement() ast.Statement {
+	stmt := &ast.ClassStatement{Token: p.curToken}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(token.EXTENDS) {
+		p.nextToken()
+
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+
+		stmt.Superclass = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	stmt.Methods = []*ast.FunctionLiteral{}
+
+	for !p.peekTokenIs(token.RBRACE) {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+
+		method := p.parseMethod()
+		if method == nil {
+			return nil
+		}
+		stmt.Methods = append(stmt.Methods, method)
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseMethod() *ast.FunctionLiteral {
+	method := &ast.FunctionLiteral{Token: p.curToken, Name: p.curToken.Literal}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
+	method.Parameters, method.ParameterTypes = p.parseParameters(true)
+
+	if !p.parseReturnType(method) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	method.Body = p.parseFunctionBody(method)
+
+	return method
+}
+
+func (p *Parser) parseEnumStatement() ast.Statement {
+	stmt := &ast.EnumStatement{Token: p.curToken}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	stmt.Name = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	stmt.Variants = []*ast.EnumVariant{}
+	seen := make(map[string]bool)
+
+	for !p.peekTokenIs(token.RBRACE) {
+		if !p.expectPeek(token.IDENT) {
+			return nil
+		}
+
+		name := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+		if seen[name.Value] {
+			msg := fmt.Sprintf("duplicate variant %s in enum %s", name.Value, stmt.Name.Value)
+			p.errors = append(p.errors, msg)
+			return nil
+		}
+		seen[name.Value] = true
+
+		variant := &ast.EnumVariant{Name: name, Fields: []*ast.Identifier{}}
+
+		if p.peekTokenIs(token.LPAREN) {
+			p.nextToken()
+			variant.Fields = p.parseFunctionParameters()
+			if variant.Fields == nil {
+				return nil
+			}
+		}
+
+		stmt.Variants = append(stmt.Variants, variant)
+
+		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseTryStatement() ast.Statement {
+	stmt := &ast.TryStatement{Token: p.curToken}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	stmt.Block = p.parseBlockStatement()
+
+	if p.peekTokenIs(token.CATCH) {
+		p.nextToken()
+
+		if p.peekTokenIs(token.LPAREN) {
+			p.nextToken()
+
+			if !p.expectPeek(token.IDENT) {
+				return nil
+			}
+
+			stmt.CatchParam = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+			if !p.expectPeek(token.RPAREN) {
+				return nil
+			}
+		}
+
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+
+		stmt.Catch = p.parseBlockStatement()
+	}
+
+	if p.peekTokenIs(token.FINALLY) {
+		p.nextToken()
+
+		if !p.expectPeek(token.LBRACE) {
+			return nil
+		}
+
+		stmt.Finally = p.parseBlockStatement()
+	}
+
+	if stmt.Catch == nil && stmt.Finally == nil {
+		p.errors = append(p.errors, "try statement requires catch or finally")
+		return nil
+	}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseThrowStatement() *ast.ThrowStatement {
+	stmt := &ast.ThrowStatement{Token: p.curToken}
+
+	p.nextToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseDeferStatement() *ast.DeferStatement {
+	stmt := &ast.DeferStatement{Token: p.curToken}
+
+	p.nextToken()
+
+	stmt.Expression = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseImportStatement() ast.Statement {
+	stmt := &ast.ImportStatement{Token: p.curToken}
+
+	if !p.expectPeek(token.STRING) {
+		return nil
+	}
+
+	stmt.Path = &ast.StringLiteral{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(token.AS) {
+		return nil
+	}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	stmt.Alias = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseExportStatement() ast.Statement {
+	stmt := &ast.ExportStatement{Token: p.curToken}
+
+	// Doc comments before `export` document the exported definition.
+	doc := p.curDoc
+	p.nextToken()
+	if p.curDoc == "" {
+		p.curDoc = doc
+	}
+
+	switch p.curToken.Type {
+	case token.LET, token.CONST, token.CLASS, token.ENUM:
+	default:
+		msg := fmt.Sprintf("export must be followed by let, const, class or enum, got %s",
+			p.curToken.Type)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+
+	// The statement parsers report failure through p.errors and may
+	// return a typed nil, so compare error counts instead.
+	errorCount := len(p.errors)
+	stmt.Statement = p.parseStatement()
+	if len(p.errors) > errorCount {
+		return nil
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseYieldStatement() ast.Statement {
+	stmt := &ast.YieldStatement{Token: p.curToken}
+
+	if len(p.functions) == 0 || p.functions[len(p.functions)-1] == nil {
+		p.errors = append(p.errors, "yield outside of function")
+		return nil
+	}
+	p.functions[len(p.functions)-1].Generator = true
+
+	p.nextToken()
+
+	stmt.Value = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+func (p *Parser) parseForStatement() ast.Statement {
+	stmt := &ast.ForStatement{Token: p.curToken}
+
+	stmt.Key, stmt.Value = p.parseLoopVariables()
+	if stmt.Value == nil {
+		return nil
+	}
+
+	if !p.expectPeek(token.IN) {
+		return nil
+	}
+
+	p.nextToken()
+	stmt.Iterable = p.parseExpression(LOWEST)
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	stmt.Body = p.parseBlockStatement()
+
+	if p.peekTokenIs(token.SEMICOLON) {
+		p.nextToken()
+	}
+
+	return stmt
+}
+
+// parseLoopVariables parses the `x` or `(k, v)` after a 'for' token. The
+// key is nil in the single-variable form; both are nil on error.
+func (p *Parser) parseLoopVariables() (*ast.Identifier, *ast.Identifier) {
+	if !p.peekTokenIs(token.LPAREN) {
+		if !p.expectPeek(token.IDENT) {
+			return nil, nil
+		}
+		return nil, &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+	}
+
+	p.nextToken()
+
+	if !p.expectPeek(token.IDENT) {
+		return nil, nil
+	}
+	key := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(token.COMMA) {
+		return nil, nil
+	}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil, nil
+	}
+	value := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	if !p.expectPeek(token.RPAREN) {
+		return nil, nil
+	}
+
+	return key, value
+}
+
 func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
+	defer p.untrace(p.trace("parseExpressionStatement"))
+
 	stmt := &ast.ExpressionStatement{Token: p.curToken}
 
 	stmt.Expression = p.parseExpression(LOWEST)
@@ -230,6 +680,8 @@ func (p *Parser) parseExpressionStatement() *ast.ExpressionStatement {
 }
 
 func (p *Parser) parseExpression(precedence int) ast.Expression {
+	defer p.untrace(p.trace("parseExpression"))
+
 	prefix := p.prefixParseFns[p.curToken.Type]
 	if prefix == nil {
 		p.noPrefixParseFnError(p.curToken.Type)
@@ -272,6 +724,8 @@ func (p *Parser) parseIdentifier() ast.Expression {
 }
 
 func (p *Parser) parseIntegerLiteral() ast.Expression {
+	defer p.untrace(p.trace("parseIntegerLiteral"))
+
 	lit := &ast.IntegerLiteral{Token: p.curToken}
 
 	value, err := strconv.ParseInt(p.curToken.Literal, 0, 64)
@@ -291,6 +745,8 @@ func (p *Parser) parseStringLiteral() ast.Expression {
 }
 
 func (p *Parser) parsePrefixExpression() ast.Expression {
+	defer p.untrace(p.trace("parsePrefixExpression"))
+
 	expression := &ast.PrefixExpression{
 		Token:    p.curToken,
 		Operator: p.curToken.Literal,
@@ -304,6 +760,8 @@ func (p *Parser) parsePrefixExpression() ast.Expression {
 }
 
 func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
+	defer p.untrace(p.trace("parseInfixExpression"))
+
 	expression := &ast.InfixExpression{
 		Token:    p.curToken,
 		Operator: p.curToken.Literal,
@@ -317,6 +775,20 @@ func (p *Parser) parseInfixExpression(left ast.Expression) ast.Expression {
 	return expression
 }
 
+func (p *Parser) parseRangeExpression(start ast.Expression) ast.Expression {
+	expression := &ast.RangeExpression{
+		Token:     p.curToken,
+		Start:     start,
+		Inclusive: p.curTokenIs(token.DOTDOT_EQ),
+	}
+
+	precedence := p.curPrecedence()
+	p.nextToken()
+	expression.End = p.parseExpression(precedence)
+
+	return expression
+}
+
 func (p *Parser) parseBoolean() ast.Expression {
 	return &ast.Boolean{Token: p.curToken, Value: p.curTokenIs(token.TRUE)}
 }
@@ -334,6 +806,8 @@ func (p *Parser) parseGroupedExpression() ast.Expression {
 }
 
 func (p *Parser) parseIfExpression() ast.Expression {
+	defer p.untrace(p.trace("parseIfExpression"))
+
 	expression := &ast.IfExpression{Token: p.curToken}
 
 	if !p.expectPeek(token.LPAREN) {
@@ -367,6 +841,8 @@ func (p *Parser) parseIfExpression() ast.Expression {
 }
 
 func (p *Parser) parseBlockStatement() *ast.BlockStatement {
+	defer p.untrace(p.trace("parseBlockStatement"))
+
 	block := &ast.BlockStatement{Token: p.curToken}
 	block.Statements = []ast.Statement{}
 
@@ -384,66 +860,194 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 }
 
 func (p *Parser) parseFunctionLiteral() ast.Expression {
+	defer p.untrace(p.trace("parseFunctionLiteral"))
+
 	lit := &ast.FunctionLiteral{Token: p.curToken}
 
 	if !p.expectPeek(token.LPAREN) {
 		return nil
 	}
 
+	lit.Parameters, lit.ParameterTypes = p.parseParameters(true)
+
+	if !p.parseReturnType(lit) {
+		return nil
+	}
+
+	if !p.expectPeek(token.LBRACE) {
+		return nil
+	}
+
+	lit.Body = p.parseFunctionBody(lit)
+
+	return lit
+}
+
+// parseFunctionBody parses the body of fn, which is nil for macros, while
+// fn is the innermost enclosing function.
+func (p *Parser) parseFunctionBody(fn *ast.FunctionLiteral) *ast.BlockStatement {
+	p.functions = append(p.functions, fn)
+	defer func() { p.functions = p.functions[:len(p.functions)-1] }()
+
+	return p.parseBlockStatement()
+}
+
+func (p *Parser) parseMacroLiteral() ast.Expression {
+	lit := &ast.MacroLiteral{Token: p.curToken}
+
+	if !p.expectPeek(token.LPAREN) {
+		return nil
+	}
+
 	lit.Parameters = p.parseFunctionParameters()
 
 	if !p.expectPeek(token.LBRACE) {
 		return nil
 	}
 
-	lit.Body = p.parseBlockStatement()
+	lit.Body = p.parseFunctionBody(nil)
 
 	return lit
 }
 
 func (p *Parser) parseFunctionParameters() []*ast.Identifier {
+	identifiers, _ := p.parseParameters(false)
+	return identifiers
+}
+
+// parseParameters parses a parenthesised parameter list. When typed is
+// true each parameter may be annotated with `: type`; the returned types
+// are parallel to the identifiers, or nil when no parameter is annotated.
+func (p *Parser) parseParameters(typed bool) ([]*ast.Identifier, []*ast.TypeAnnotation) {
 	identifiers := []*ast.Identifier{}
+	types := []*ast.TypeAnnotation{}
+	annotated := false
 
 	if p.peekTokenIs(token.RPAREN) {
 		p.nextToken()
-		return identifiers
+		return identifiers, nil
 	}
 
-	p.nextToken()
-
-	ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
-	identifiers = append(identifiers, ident)
-
-	for p.peekTokenIs(token.COMMA) {
-		p.nextToken()
+	for {
 		p.nextToken()
+
 		ident := &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
 		identifiers = append(identifiers, ident)
+
+		var typ *ast.TypeAnnotation
+		if typed && p.peekTokenIs(token.COLON) {
+			p.nextToken()
+			p.nextToken()
+
+			typ = p.parseTypeAnnotation()
+			if typ == nil {
+				return nil, nil
+			}
+			annotated = true
+		}
+		types = append(types, typ)
+
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken()
 	}
 
 	if !p.expectPeek(token.RPAREN) {
+		return nil, nil
+	}
+
+	if !annotated {
+		types = nil
+	}
+
+	return identifiers, types
+}
+
+// parseReturnType parses an optional `-> type` after the parameters of fn.
+// It reports false on a malformed type.
+func (p *Parser) parseReturnType(fn *ast.FunctionLiteral) bool {
+	if !p.peekTokenIs(token.ARROW) {
+		return true
+	}
+
+	p.nextToken()
+	p.nextToken()
+
+	fn.ReturnType = p.parseTypeAnnotation()
+	return fn.ReturnType != nil
+}
+
+// parseTypeAnnotation parses a type starting at the current token: a name
+// such as int, [elem] or {key: value}.
+func (p *Parser) parseTypeAnnotation() *ast.TypeAnnotation {
+	typ := &ast.TypeAnnotation{Token: p.curToken}
+
+	switch p.curToken.Type {
+	case token.IDENT:
+		typ.Name = p.curToken.Literal
+
+	case token.FUNCTION:
+		typ.Name = p.curToken.Literal
+
+	case token.LBRACKET:
+		p.nextToken()
+
+		typ.Elem = p.parseTypeAnnotation()
+		if typ.Elem == nil || !p.expectPeek(token.RBRACKET) {
+			return nil
+		}
+
+	case token.LBRACE:
+		p.nextToken()
+
+		typ.Key = p.parseTypeAnnotation()
+		if typ.Key == nil || !p.expectPeek(token.COLON) {
+			return nil
+		}
+
+		p.nextToken()
+
+		typ.Elem = p.parseTypeAnnotation()
+		if typ.Elem == nil || !p.expectPeek(token.RBRACE) {
+			return nil
+		}
+
+	default:
+		msg := fmt.Sprintf("expected a type, got %s", p.curToken.Type)
+		p.errors = append(p.errors, msg)
 		return nil
 	}
 
-	return identifiers
+	return typ
 }
 
 func (p *Parser) parseCallExpression(function ast.Expression) ast.Expression {
+	defer p.untrace(p.trace("parseCallExpression"))
+
 	exp := &ast.CallExpression{Token: p.curToken, Function: function}
 	exp.Arguments = p.parseExpressionList(token.RPAREN)
 	return exp
 }
 
 func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
-	list := []ast.Expression{}
-
 	if p.peekTokenIs(end) {
 		p.nextToken()
-		return list
+		return []ast.Expression{}
 	}
 
 	p.nextToken()
-	list = append(list, p.parseExpression(LOWEST))
+
+	return p.parseExpressionListFrom(p.parseExpression(LOWEST), end)
+}
+
+// parseExpressionListFrom parses the rest of a list whose first element
+// has already been parsed.
+func (p *Parser) parseExpressionListFrom(
+	first ast.Expression,
+	end token.TokenType,
+) []ast.Expression {
+	list := []ast.Expression{first}
 
 	for p.peekTokenIs(token.COMMA) {
 		p.nextToken()
@@ -461,16 +1065,111 @@ func (p *Parser) parseExpressionList(end token.TokenType) []ast.Expression {
 func (p *Parser) parseArrayLiteral() ast.Expression {
 	array := &ast.ArrayLiteral{Token: p.curToken}
 
-	array.Elements = p.parseExpressionList(token.RBRACKET)
+	if p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+		array.Elements = []ast.Expression{}
+		return array
+	}
+
+	p.nextToken()
+	first := p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.FOR) {
+		return p.parseArrayComprehension(array.Token, first)
+	}
+
+	array.Elements = p.parseExpressionListFrom(first, token.RBRACKET)
 
 	return array
 }
 
+func (p *Parser) parseArrayComprehension(
+	tok token.Token,
+	element ast.Expression,
+) ast.Expression {
+	p.nextToken()
+
+	clause := p.parseComprehensionClause()
+	if clause == nil {
+		return nil
+	}
+
+	if !p.expectPeek(token.RBRACKET) {
+		return nil
+	}
+
+	return &ast.ArrayComprehension{Token: tok, Element: element, Clause: clause}
+}
+
+// parseComprehensionClause parses `for x in xs if cond` starting at the
+// 'for' token. The `if` filter is optional.
+func (p *Parser) parseComprehensionClause() *ast.ComprehensionClause {
+	clause := &ast.ComprehensionClause{Token: p.curToken}
+
+	clause.Key, clause.Value = p.parseLoopVariables()
+	if clause.Value == nil {
+		return nil
+	}
+
+	if !p.expectPeek(token.IN) {
+		return nil
+	}
+
+	p.nextToken()
+	clause.Iterable = p.parseExpression(LOWEST)
+
+	if p.peekTokenIs(token.IF) {
+		p.nextToken()
+		p.nextToken()
+		clause.Condition = p.parseExpression(LOWEST)
+	}
+
+	return clause
+}
+
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
-	exp := &ast.IndexExpression{Token: p.curToken, Left: left}
+	tok := p.curToken
+
+	var start ast.Expression
+	if !p.peekTokenIs(token.COLON) {
+		p.nextToken()
+		start = p.parseExpression(LOWEST)
+
+		if !p.peekTokenIs(token.COLON) {
+			if !p.expectPeek(token.RBRACKET) {
+				return nil
+			}
+
+			return &ast.IndexExpression{Token: tok, Left: left, Index: start}
+		}
+	}
+
+	return p.parseSliceExpression(tok, left, start)
+}
+
+// parseSliceExpression parses the rest of left[start:end:step] once the
+// parser is about to read the first ':'.
+func (p *Parser) parseSliceExpression(
+	tok token.Token,
+	left, start ast.Expression,
+) ast.Expression {
+	exp := &ast.SliceExpression{Token: tok, Left: left, Start: start}
 
 	p.nextToken()
-	exp.Index = p.parseExpression(LOWEST)
+
+	if !p.peekTokenIs(token.COLON) && !p.peekTokenIs(token.RBRACKET) {
+		p.nextToken()
+		exp.End = p.parseExpression(LOWEST)
+	}
+
+	if p.peekTokenIs(token.COLON) {
+		p.nextToken()
+
+		if !p.peekTokenIs(token.RBRACKET) {
+			p.nextToken()
+			exp.Step = p.parseExpression(LOWEST)
+		}
+	}
 
 	if !p.expectPeek(token.RBRACKET) {
 		return nil
@@ -479,9 +1178,43 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	return exp
 }
 
+func (p *Parser) parseMemberExpression(object ast.Expression) ast.Expression {
+	exp := &ast.MemberExpression{Token: p.curToken, Object: object}
+
+	if !p.expectPeek(token.IDENT) {
+		return nil
+	}
+
+	exp.Property = &ast.Identifier{Token: p.curToken, Value: p.curToken.Literal}
+
+	return exp
+}
+
+func (p *Parser) parseAssignExpression(target ast.Expression) ast.Expression {
+	exp := &ast.AssignExpression{
+		Token:    p.curToken,
+		Operator: p.curToken.Literal,
+		Target:   target,
+	}
+
+	switch target.(type) {
+	case *ast.Identifier, *ast.MemberExpression, *ast.IndexExpression:
+	default:
+		msg := fmt.Sprintf("invalid assignment target %s", target)
+		p.errors = append(p.errors, msg)
+		return nil
+	}
+
+	// Parse the right-hand side one level lower so assignment is right-associative
+	p.nextToken()
+	exp.Value = p.parseExpression(ASSIGN - 1)
+
+	return exp
+}
+
 func (p *Parser) parseHashLiteral() ast.Expression {
 	hash := &ast.HashLiteral{Token: p.curToken}
-	hash.Pairs = make(map[ast.Expression]ast.Expression)
+	hash.Pairs = []ast.HashPair{}
 
 	for !p.peekTokenIs(token.RBRACE) {
 		p.nextToken()
@@ -494,7 +1227,11 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 		p.nextToken()
 		value := p.parseExpression(LOWEST)
 
-		hash.Pairs[key] = value
+		if len(hash.Pairs) == 0 && p.peekTokenIs(token.FOR) {
+			return p.parseHashComprehension(hash.Token, key, value)
+		}
+
+		hash.Pairs = append(hash.Pairs, ast.HashPair{Key: key, Value: value})
 
 		if !p.peekTokenIs(token.RBRACE) && !p.expectPeek(token.COMMA) {
 			return nil
@@ -508,6 +1245,24 @@ func (p *Parser) parseHashLiteral() ast.Expression {
 	return hash
 }
 
+func (p *Parser) parseHashComprehension(
+	tok token.Token,
+	key, value ast.Expression,
+) ast.Expression {
+	p.nextToken()
+
+	clause := p.parseComprehensionClause()
+	if clause == nil {
+		return nil
+	}
+
+	if !p.expectPeek(token.RBRACE) {
+		return nil
+	}
+
+	return &ast.HashComprehension{Token: tok, Key: key, Value: value, Clause: clause}
+}
+
 func (p *Parser) registerPrefix(tokenType token.TokenType, fn prefixParseFn) {
 	p.prefixParseFns[tokenType] = fn
 }
@@ -7,10 +7,12 @@ import (
 	"fmt"
 	"io"
 
+	"github.com/Devashish08/InterPreter-Compiler/ast"
 	"github.com/Devashish08/InterPreter-Compiler/evaluator"
 	"github.com/Devashish08/InterPreter-Compiler/lexer"
 	"github.com/Devashish08/InterPreter-Compiler/object"
 	"github.com/Devashish08/InterPreter-Compiler/parser"
+	"github.com/Devashish08/InterPreter-Compiler/resolver"
 )
 
 // PROMPT defines the interactive prompt symbol
@@ -24,7 +26,10 @@ const PROMPT = ">> "
 // 5. Repeats until EOF/exit
 func Start(in io.Reader, out io.Writer) {
 	scanner := bufio.NewScanner(in)
-	env := object.NewEnvironment()
+	loader := evaluator.NewModuleLoader(nil)
+	loader.CheckNames = resolver.Check
+	env := loader.NewEnvironment("")
+	macroEnv := object.NewEnvironment()
 
 	for {
 		fmt.Print(PROMPT)
@@ -43,13 +48,49 @@ func Start(in io.Reader, out io.Writer) {
 			continue
 		}
 
-		evaluated := evaluator.Eval(program, env)
+		evaluator.DefineMacros(program, macroEnv)
+		expanded, err := evaluator.ExpandMacros(program, macroEnv)
+		if err != nil {
+			io.WriteString(out, "macro expansion error: "+err.Error()+"\n")
+			continue
+		}
+
+		if !checkNames(out, expanded.(*ast.Program), env) {
+			continue
+		}
+
+		evaluated := evaluator.Eval(expanded, env)
 		if evaluated != nil {
 			io.WriteString(out, evaluated.Inspect())
 			io.WriteString(out, "\n")
 		}
 	}
 }
+
+// checkNames resolves the names of program, which may use the names bound
+// in env by earlier lines. It prints the problems found and reports
+// whether program can run.
+func checkNames(out io.Writer, program *ast.Program, env *object.Environment) bool {
+	result := resolver.Resolve(program, env.Names()...)
+
+	for _, d := range result.Diagnostics {
+		if d.Warning() {
+			io.WriteString(out, "warning: "+d.String()+"\n")
+		}
+	}
+
+	errors := result.Errors()
+	if len(errors) == 0 {
+		return true
+	}
+
+	io.WriteString(out, "name errors:\n")
+	for _, d := range errors {
+		io.WriteString(out, "\t"+d.String()+"\n")
+	}
+	return false
+}
+
 func printParserErrors(out io.Writer, errors []string) {
 	io.WriteString(out, "Woops! We got problem here!\n")
 	io.WriteString(out, " parser errors:\n")
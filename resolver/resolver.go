@@ -0,0 +1,445 @@
+/*
+Package resolver checks the names a program uses before it runs.
+
+Resolve builds a symbol table for each scope of a program, following the
+scopes the evaluator creates at run time: the global scope, one scope per
+function call, loop iteration, catch clause and comprehension, and the
+builtin functions around them all. Blocks of if expressions and try
+statements share the scope they appear in.
+
+It reports:
+
+  - identifiers that are used but never bound
+  - functions and macros that name a parameter twice
+  - bindings that shadow a builtin function
+
+Function bodies are resolved after the scope that defines them is
+complete, since they only run once called. A function may therefore use
+names that are bound after it, such as itself or functions defined later.
+*/
+package resolver
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/evaluator"
+	"github.com/Devashish08/InterPreter-Compiler/token"
+)
+
+// Problem identifies the kind of a Diagnostic.
+type Problem string
+
+const (
+	Undefined          Problem = "undefined"
+	DuplicateParameter Problem = "duplicate-parameter"
+	ShadowedBuiltin    Problem = "shadowed-builtin"
+)
+
+// Diagnostic is a problem found at a position in the source.
+type Diagnostic struct {
+	Problem Problem
+	Line    int
+	Column  int
+	Message string
+}
+
+// Warning reports whether the program can still run as intended despite
+// d. Only shadowed builtins are warnings.
+func (d Diagnostic) Warning() bool {
+	return d.Problem == ShadowedBuiltin
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("line %d, column %d: %s", d.Line, d.Column, d.Message)
+}
+
+// SymbolKind tells how a symbol was bound.
+type SymbolKind string
+
+const (
+	Variable      SymbolKind = "variable" // let or const
+	Parameter     SymbolKind = "parameter"
+	LoopVariable  SymbolKind = "loop variable" // for loops and comprehensions
+	CatchVariable SymbolKind = "catch variable"
+	Class         SymbolKind = "class"
+	Enum          SymbolKind = "enum"
+	Import        SymbolKind = "import"
+	Receiver      SymbolKind = "receiver" // self and super inside methods
+	Builtin       SymbolKind = "builtin"
+	Global        SymbolKind = "global" // bound before the program runs
+)
+
+// Symbol is a name bound in a scope.
+type Symbol struct {
+	Name string
+	Kind SymbolKind
+	// Decl is the identifier that binds the name. It is nil for receivers,
+	// builtins and globals.
+	Decl *ast.Identifier
+	// References holds the identifiers that resolved to the symbol, in the
+	// order they were resolved.
+	References []*ast.Identifier
+}
+
+// Scope is a symbol table.
+type Scope struct {
+	Outer   *Scope
+	Symbols map[string]*Symbol
+}
+
+func newScope(outer *Scope) *Scope {
+	return &Scope{Outer: outer, Symbols: make(map[string]*Symbol)}
+}
+
+// Lookup returns the symbol name refers to in s, searching outer scopes
+// when s does not bind it.
+func (s *Scope) Lookup(name string) (*Symbol, bool) {
+	for scope := s; scope != nil; scope = scope.Outer {
+		if symbol, ok := scope.Symbols[name]; ok {
+			return symbol, true
+		}
+	}
+	return nil, false
+}
+
+// Result is the outcome of resolving a program.
+type Result struct {
+	// Diagnostics holds the problems found, ordered by position.
+	Diagnostics []Diagnostic
+	// Symbols holds every symbol the program binds, in the order they
+	// were bound, without builtins.
+	Symbols []*Symbol
+	// References maps each identifier that reads or assigns a name to the
+	// symbol it resolved to. Undefined identifiers are missing.
+	References map[*ast.Identifier]*Symbol
+}
+
+// Errors returns the diagnostics that are not warnings.
+func (r *Result) Errors() []Diagnostic {
+	var errors []Diagnostic
+	for _, d := range r.Diagnostics {
+		if !d.Warning() {
+			errors = append(errors, d)
+		}
+	}
+	return errors
+}
+
+// Resolve resolves every identifier of program. globals are names bound
+// before the program runs, such as those of earlier lines in the REPL.
+func Resolve(program *ast.Program, globals ...string) *Result {
+	builtins := newScope(nil)
+	for name := range evaluator.GetBuiltins() {
+		builtins.Symbols[name] = &Symbol{Name: name, Kind: Builtin}
+	}
+
+	r := &resolver{
+		builtins: builtins,
+		result:   &Result{References: make(map[*ast.Identifier]*Symbol)},
+	}
+
+	predeclared := newScope(builtins)
+	for _, name := range globals {
+		predeclared.Symbols[name] = &Symbol{Name: name, Kind: Global}
+	}
+
+	global := newScope(predeclared)
+	r.statements(program.Statements, global)
+
+	for len(r.pending) > 0 {
+		next := r.pending[0]
+		r.pending = r.pending[1:]
+		next()
+	}
+
+	sort.SliceStable(r.result.Diagnostics, func(i, j int) bool {
+		a, b := r.result.Diagnostics[i], r.result.Diagnostics[j]
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+
+	return r.result
+}
+
+// Check resolves program and returns its errors joined into one, or nil.
+// It serves as evaluator.ModuleLoader.CheckNames.
+func Check(program *ast.Program) error {
+	errors := Resolve(program).Errors()
+	if len(errors) == 0 {
+		return nil
+	}
+
+	messages := make([]string, len(errors))
+	for i, d := range errors {
+		messages[i] = d.String()
+	}
+	return fmt.Errorf("%s", strings.Join(messages, "; "))
+}
+
+type resolver struct {
+	builtins *Scope
+	result   *Result
+	// pending holds the function bodies and deferred expressions still to
+	// resolve.
+	pending []func()
+}
+
+func (r *resolver) report(problem Problem, tok token.Token, format string, args ...interface{}) {
+	r.result.Diagnostics = append(r.result.Diagnostics, Diagnostic{
+		Problem: problem,
+		Line:    tok.Line,
+		Column:  tok.Column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// declare binds ident in scope.
+func (r *resolver) declare(scope *Scope, ident *ast.Identifier, kind SymbolKind) {
+	if _, ok := r.builtins.Symbols[ident.Value]; ok {
+		r.report(ShadowedBuiltin, ident.Token, "%s %s shadows a builtin function", kind, ident.Value)
+	}
+
+	symbol := &Symbol{Name: ident.Value, Kind: kind, Decl: ident}
+	scope.Symbols[ident.Value] = symbol
+	r.result.Symbols = append(r.result.Symbols, symbol)
+}
+
+// reference resolves a use of ident in scope.
+func (r *resolver) reference(scope *Scope, ident *ast.Identifier) {
+	symbol, ok := scope.Lookup(ident.Value)
+	if !ok {
+		r.report(Undefined, ident.Token, "identifier not found: %s", ident.Value)
+		return
+	}
+
+	symbol.References = append(symbol.References, ident)
+	r.result.References[ident] = symbol
+}
+
+// parameters binds the parameters of a function or macro in scope.
+func (r *resolver) parameters(scope *Scope, params []*ast.Identifier) {
+	for _, param := range params {
+		if symbol, ok := scope.Symbols[param.Value]; ok && symbol.Kind == Parameter {
+			r.report(DuplicateParameter, param.Token, "duplicate parameter %s", param.Value)
+		}
+		r.declare(scope, param, Parameter)
+	}
+}
+
+// later resolves body in a new scope enclosed by scope once the current
+// scopes are complete. bind adds the names the call binds.
+func (r *resolver) later(scope *Scope, body *ast.BlockStatement, bind func(*Scope)) {
+	r.pending = append(r.pending, func() {
+		inner := newScope(scope)
+		bind(inner)
+		r.statements(body.Statements, inner)
+	})
+}
+
+func (r *resolver) statements(stmts []ast.Statement, scope *Scope) {
+	for _, stmt := range stmts {
+		if stmt != nil {
+			r.statement(stmt, scope)
+		}
+	}
+}
+
+func (r *resolver) statement(stmt ast.Statement, scope *Scope) {
+	switch s := stmt.(type) {
+	case *ast.LetStatement:
+		r.expression(s.Value, scope)
+		r.declare(scope, s.Name, Variable)
+
+	case *ast.ReturnStatement:
+		r.expression(s.ReturnValue, scope)
+
+	case *ast.ExpressionStatement:
+		r.expression(s.Expression, scope)
+
+	case *ast.BlockStatement:
+		r.statements(s.Statements, scope)
+
+	case *ast.ClassStatement:
+		if s.Superclass != nil {
+			r.reference(scope, s.Superclass)
+		}
+		r.declare(scope, s.Name, Class)
+
+		for _, method := range s.Methods {
+			method := method
+			r.later(scope, method.Body, func(inner *Scope) {
+				inner.Symbols["self"] = &Symbol{Name: "self", Kind: Receiver}
+				if s.Superclass != nil {
+					inner.Symbols["super"] = &Symbol{Name: "super", Kind: Receiver}
+				}
+				r.parameters(inner, method.Parameters)
+			})
+		}
+
+	case *ast.EnumStatement:
+		r.declare(scope, s.Name, Enum)
+
+	case *ast.TryStatement:
+		r.statements(s.Block.Statements, scope)
+		if s.Catch != nil {
+			inner := newScope(scope)
+			if s.CatchParam != nil {
+				r.declare(inner, s.CatchParam, CatchVariable)
+			}
+			r.statements(s.Catch.Statements, inner)
+		}
+		if s.Finally != nil {
+			r.statements(s.Finally.Statements, scope)
+		}
+
+	case *ast.ThrowStatement:
+		r.expression(s.Value, scope)
+
+	case *ast.DeferStatement:
+		// The expression runs when the function returns, after the rest
+		// of its body has declared its names.
+		r.pending = append(r.pending, func() {
+			r.expression(s.Expression, scope)
+		})
+
+	case *ast.ImportStatement:
+		r.declare(scope, s.Alias, Import)
+
+	case *ast.ExportStatement:
+		if s.Statement != nil {
+			r.statement(s.Statement, scope)
+		}
+
+	case *ast.YieldStatement:
+		r.expression(s.Value, scope)
+
+	case *ast.ForStatement:
+		r.expression(s.Iterable, scope)
+		inner := newScope(scope)
+		r.loopVariables(inner, s.Key, s.Value)
+		r.statements(s.Body.Statements, inner)
+	}
+}
+
+func (r *resolver) loopVariables(scope *Scope, key, value *ast.Identifier) {
+	if key != nil {
+		r.declare(scope, key, LoopVariable)
+	}
+	r.declare(scope, value, LoopVariable)
+}
+
+func (r *resolver) expressions(exprs []ast.Expression, scope *Scope) {
+	for _, expr := range exprs {
+		r.expression(expr, scope)
+	}
+}
+
+func (r *resolver) expression(expr ast.Expression, scope *Scope) {
+	switch e := expr.(type) {
+	case nil:
+		// optional expressions, such as a missing slice bound
+
+	case *ast.Identifier:
+		r.reference(scope, e)
+
+	case *ast.PrefixExpression:
+		r.expression(e.Right, scope)
+
+	case *ast.InfixExpression:
+		r.expression(e.Left, scope)
+		r.expression(e.Right, scope)
+
+	case *ast.IfExpression:
+		r.expression(e.Condition, scope)
+		r.statements(e.Consequence.Statements, scope)
+		if e.Alternative != nil {
+			r.statements(e.Alternative.Statements, scope)
+		}
+
+	case *ast.FunctionLiteral:
+		r.later(scope, e.Body, func(inner *Scope) {
+			r.parameters(inner, e.Parameters)
+		})
+
+	case *ast.MacroLiteral:
+		r.later(scope, e.Body, func(inner *Scope) {
+			r.parameters(inner, e.Parameters)
+		})
+
+	case *ast.CallExpression:
+		if e.Function.TokenLiteral() == "quote" {
+			r.quoted(e.Arguments, scope)
+			return
+		}
+		r.expression(e.Function, scope)
+		r.expressions(e.Arguments, scope)
+
+	case *ast.ArrayLiteral:
+		r.expressions(e.Elements, scope)
+
+	case *ast.IndexExpression:
+		r.expression(e.Left, scope)
+		r.expression(e.Index, scope)
+
+	case *ast.SliceExpression:
+		r.expression(e.Left, scope)
+		r.expression(e.Start, scope)
+		r.expression(e.End, scope)
+		r.expression(e.Step, scope)
+
+	case *ast.HashLiteral:
+		for _, pair := range e.Pairs {
+			r.expression(pair.Key, scope)
+			r.expression(pair.Value, scope)
+		}
+
+	case *ast.MemberExpression:
+		r.expression(e.Object, scope)
+
+	case *ast.AssignExpression:
+		r.expression(e.Value, scope)
+		r.expression(e.Target, scope)
+
+	case *ast.RangeExpression:
+		r.expression(e.Start, scope)
+		r.expression(e.End, scope)
+
+	case *ast.ArrayComprehension:
+		inner := r.clause(e.Clause, scope)
+		r.expression(e.Element, inner)
+
+	case *ast.HashComprehension:
+		inner := r.clause(e.Clause, scope)
+		r.expression(e.Key, inner)
+		r.expression(e.Value, inner)
+	}
+}
+
+// clause resolves a comprehension clause and returns the scope of its
+// loop variables.
+func (r *resolver) clause(c *ast.ComprehensionClause, scope *Scope) *Scope {
+	r.expression(c.Iterable, scope)
+
+	inner := newScope(scope)
+	r.loopVariables(inner, c.Key, c.Value)
+	r.expression(c.Condition, inner)
+
+	return inner
+}
+
+// quoted resolves the arguments of a quote call. Only the arguments of the
+// unquote calls inside them are evaluated.
+func (r *resolver) quoted(args []ast.Expression, scope *Scope) {
+	for _, arg := range args {
+		ast.Inspect(arg, func(node ast.Node) bool {
+			call, ok := node.(*ast.CallExpression)
+			if !ok || call.Function.TokenLiteral() != "unquote" {
+				return true
+			}
+			r.expressions(call.Arguments, scope)
+			return false
+		})
+	}
+}
@@ -0,0 +1,207 @@
+package resolver
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/lexer"
+	"github.com/Devashish08/InterPreter-Compiler/parser"
+)
+
+func TestResolveValidPrograms(t *testing.T) {
+	tests := []string{
+		"let x = 1; x + len([x]);",
+		"let fib = fn(n) { if (n < 2) { n } else { fib(n - 1) + fib(n - 2) } }; fib(5);",
+		"let isEven = fn(n) { if (n == 0) { true } else { isOdd(n - 1) } }; let isOdd = fn(n) { !isEven(n) };",
+		"let adder = fn(x) { fn(y) { x + y } }; adder(1)(2);",
+		"if (true) { let y = 1; } y;",
+		"try { let z = 1; } catch (e) { puts(e); } finally { z; }",
+		"let total = 0; for (i, x) in [1, 2] { total += i * x; }",
+		"let xs = [1, 2]; [x * 2 for x in xs if x > 0];",
+		"let h = {1: 2}; {k: v for (k, v) in h if v > k};",
+		"class A { init(v) { self.v = v; } get() { self.v } } class B extends A { get() { super.get() } } B(1);",
+		"enum Shape { Circle(r), Empty } Shape.Circle(1);",
+		`import "lib" as lib; lib.f();`,
+		"let q = quote(undefinedName + unquote(1 + 2));",
+		"let m = macro(a, b) { quote(unquote(a) + unquote(b)) };",
+		"let g = fn() { yield 1; }; let gen = g(); next(gen);",
+		"export let e = 1; export const c = e;",
+		"let f = fn() { defer puts(x); let x = 1; }; f();",
+		"let f = fn() { if (true) { defer puts(x); } let x = 1; };",
+	}
+
+	for _, input := range tests {
+		result := Resolve(parse(t, input))
+		if len(result.Diagnostics) != 0 {
+			t.Errorf("unexpected diagnostics for %q: %v", input, result.Diagnostics)
+		}
+	}
+}
+
+func TestResolveDiagnostics(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected []Diagnostic
+	}{
+		{
+			"let x = 1;\nif (x > 1) { puts(y); }",
+			[]Diagnostic{{Undefined, 2, 19, "identifier not found: y"}},
+		},
+		{
+			"let x = x + 1;",
+			[]Diagnostic{{Undefined, 1, 9, "identifier not found: x"}},
+		},
+		{
+			"z = 5;",
+			[]Diagnostic{{Undefined, 1, 1, "identifier not found: z"}},
+		},
+		{
+			"for x in [1] { let inner = x; } inner;",
+			[]Diagnostic{{Undefined, 1, 33, "identifier not found: inner"}},
+		},
+		{
+			"[x for x in [1]]; x;",
+			[]Diagnostic{{Undefined, 1, 19, "identifier not found: x"}},
+		},
+		{
+			"let f = fn() { defer puts(y); };",
+			[]Diagnostic{{Undefined, 1, 27, "identifier not found: y"}},
+		},
+		{
+			"try { 1; } catch (e) { 2; } e;",
+			[]Diagnostic{{Undefined, 1, 29, "identifier not found: e"}},
+		},
+		{
+			"let f = fn() { self };",
+			[]Diagnostic{{Undefined, 1, 16, "identifier not found: self"}},
+		},
+		{
+			"class A { m() { super.m() } }",
+			[]Diagnostic{{Undefined, 1, 17, "identifier not found: super"}},
+		},
+		{
+			"class B extends Missing {}",
+			[]Diagnostic{{Undefined, 1, 17, "identifier not found: Missing"}},
+		},
+		{
+			"let f = fn(a, b, a) { a + b };",
+			[]Diagnostic{{DuplicateParameter, 1, 18, "duplicate parameter a"}},
+		},
+		{
+			"class A { m(x, x) { x } }",
+			[]Diagnostic{{DuplicateParameter, 1, 16, "duplicate parameter x"}},
+		},
+		{
+			"let len = fn(puts) { puts };",
+			[]Diagnostic{
+				{ShadowedBuiltin, 1, 5, "variable len shadows a builtin function"},
+				{ShadowedBuiltin, 1, 14, "parameter puts shadows a builtin function"},
+			},
+		},
+		{
+			"let outer = fn() { let f = fn() { missing(); }; f };\nunknown;",
+			[]Diagnostic{
+				{Undefined, 1, 35, "identifier not found: missing"},
+				{Undefined, 2, 1, "identifier not found: unknown"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		result := Resolve(parse(t, tt.input))
+
+		if len(result.Diagnostics) != len(tt.expected) {
+			t.Errorf("wrong number of diagnostics for %q. want=%v, got=%v",
+				tt.input, tt.expected, result.Diagnostics)
+			continue
+		}
+
+		for i, d := range result.Diagnostics {
+			if d != tt.expected[i] {
+				t.Errorf("wrong diagnostic for %q. want=%v, got=%v", tt.input, tt.expected[i], d)
+			}
+		}
+	}
+}
+
+func TestResolveSymbols(t *testing.T) {
+	program := parse(t, "let a = 1; let f = fn(b) { a + b }; f(a);")
+	result := Resolve(program)
+
+	expected := []struct {
+		name       string
+		kind       SymbolKind
+		references int
+	}{
+		{"a", Variable, 2},
+		{"f", Variable, 1},
+		{"b", Parameter, 1},
+	}
+
+	if len(result.Symbols) != len(expected) {
+		t.Fatalf("wrong number of symbols. want=%d, got=%d", len(expected), len(result.Symbols))
+	}
+
+	for i, tt := range expected {
+		symbol := result.Symbols[i]
+		if symbol.Name != tt.name || symbol.Kind != tt.kind || len(symbol.References) != tt.references {
+			t.Errorf("symbols[%d] wrong. want=%s %s with %d references, got=%s %s with %d",
+				i, tt.kind, tt.name, tt.references,
+				symbol.Kind, symbol.Name, len(symbol.References))
+		}
+		for _, ref := range symbol.References {
+			if result.References[ref] != symbol {
+				t.Errorf("References[%s] does not point to symbols[%d]", ref.Value, i)
+			}
+		}
+	}
+
+	if errors := result.Errors(); len(errors) != 0 {
+		t.Errorf("unexpected errors: %v", errors)
+	}
+}
+
+func TestResolveGlobals(t *testing.T) {
+	result := Resolve(parse(t, "let y = x + 1; let f = fn() { x + y }; x = y;"), "x")
+	if len(result.Diagnostics) != 0 {
+		t.Fatalf("unexpected diagnostics: %v", result.Diagnostics)
+	}
+
+	for _, symbol := range result.Symbols {
+		if symbol.Name == "x" {
+			t.Errorf("global x listed among the program's symbols")
+		}
+	}
+
+	result = Resolve(parse(t, "x + z;"), "x")
+	expected := []Diagnostic{{Undefined, 1, 5, "identifier not found: z"}}
+	if !reflect.DeepEqual(result.Diagnostics, expected) {
+		t.Errorf("wrong diagnostics. want=%v, got=%v", expected, result.Diagnostics)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	if err := Check(parse(t, "let a = 1; a;")); err != nil {
+		t.Errorf("unexpected error: %s", err)
+	}
+
+	err := Check(parse(t, "let len = 1; a;\nfn(p, p) { b };"))
+	expected := "line 1, column 14: identifier not found: a; " +
+		"line 2, column 7: duplicate parameter p; " +
+		"line 2, column 12: identifier not found: b"
+	if err == nil || err.Error() != expected {
+		t.Errorf("wrong error. want=%q, got=%v", expected, err)
+	}
+}
+
+func parse(t *testing.T, input string) *ast.Program {
+	t.Helper()
+
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors for %q: %v", input, p.Errors())
+	}
+	return program
+}
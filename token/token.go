@@ -5,6 +5,8 @@ type TokenType string
 type Token struct {
 	Type    TokenType
 	Literal string
+	Line    int // 1-based line of the first character
+	Column  int // 1-based column of the first character
 }
 
 const (
@@ -15,6 +17,9 @@ const (
 	IDENT  = "IDENT"  // add, foobar, x, y, ...
 	INT    = "INT"    // 123456
 	STRING = "STRING" // "foobar"
+
+	COMMENT     = "COMMENT"     // // to the end of the line
+	DOC_COMMENT = "DOC_COMMENT" // /// documenting the definition that follows
 	// FLOAT = "FLOAT" // 123.456
 	// HEX   = "HEX"   // 0x1234
 	// OCTAL = "OCTAL" // 01234
@@ -27,12 +32,21 @@ const (
 	ASTERISK = "*"
 	SLASH    = "/"
 
+	PLUS_ASSIGN     = "+="
+	MINUS_ASSIGN    = "-="
+	ASTERISK_ASSIGN = "*="
+	SLASH_ASSIGN    = "/="
+
 	LT = "<"
 	GT = ">"
 	// Delimiters
 	COMMA     = ","
 	SEMICOLON = ";"
 	COLON     = ":"
+	DOT       = "."
+	DOTDOT    = ".."
+	DOTDOT_EQ = "..="
+	ARROW     = "->"
 
 	EQ     = "=="
 	NOT_EQ = "!="
@@ -52,16 +66,48 @@ const (
 	IF       = "IF"
 	ELSE     = "ELSE"
 	RETURN   = "RETURN"
+	CLASS    = "CLASS"
+	EXTENDS  = "EXTENDS"
+	ENUM     = "ENUM"
+	TRY      = "TRY"
+	CATCH    = "CATCH"
+	FINALLY  = "FINALLY"
+	THROW    = "THROW"
+	DEFER    = "DEFER"
+	MACRO    = "MACRO"
+	FOR      = "FOR"
+	IN       = "IN"
+	CONST    = "CONST"
+	YIELD    = "YIELD"
+	IMPORT   = "IMPORT"
+	AS       = "AS"
+	EXPORT   = "EXPORT"
 )
 
 var keywords = map[string]TokenType{
-	"fn":     FUNCTION,
-	"let":    LET,
-	"true":   TRUE,
-	"false":  FALSE,
-	"if":     IF,
-	"else":   ELSE,
-	"return": RETURN,
+	"fn":      FUNCTION,
+	"let":     LET,
+	"true":    TRUE,
+	"false":   FALSE,
+	"if":      IF,
+	"else":    ELSE,
+	"return":  RETURN,
+	"class":   CLASS,
+	"extends": EXTENDS,
+	"enum":    ENUM,
+	"try":     TRY,
+	"catch":   CATCH,
+	"finally": FINALLY,
+	"throw":   THROW,
+	"defer":   DEFER,
+	"macro":   MACRO,
+	"for":     FOR,
+	"in":      IN,
+	"const":   CONST,
+	"yield":   YIELD,
+	"import":  IMPORT,
+	"as":      AS,
+	"export":  EXPORT,
 }
 
 func LookupIdent(ident string) TokenType {
@@ -0,0 +1,886 @@
+/*
+Package typecheck infers the types of a program before it runs, for
+`interpreter check`.
+
+Inference follows Hindley-Milner: every expression gets a type, type
+variables stand for what is not known yet and are bound by unification,
+and functions bound with let are generalized, so that after
+`let id = fn(x) { x };` id has type fn(a) -> a and takes values of any
+type. The types of the builtin functions are built in.
+
+The language stays dynamically typed, so the checker only reports
+operations that fail whenever they run, such as len(5), "a" - 1 or passing
+a function where an array is needed. What it cannot follow gets the type
+any, which is compatible with every type, so that programs it does not
+understand are accepted rather than rejected:
+
+  - instances, classes, enums, modules and errors are any
+  - a variable or parameter that is assigned after its declaration is any
+  - where the branches of an if expression, the elements of an array or
+    hash, or the returns of a function differ in type, the type is any
+  - a function received as a parameter is only known to be callable
+  - an operator applied to a value of unknown type infers nothing about
+    it when a class of the program overloads the operator, or when the
+    program imports modules, whose classes may
+*/
+package typecheck
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/evaluator"
+	"github.com/Devashish08/InterPreter-Compiler/resolver"
+	"github.com/Devashish08/InterPreter-Compiler/token"
+)
+
+// Error is a type error found at a position in the source.
+type Error struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e Error) String() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+// Binding is a top-level let binding with its inferred type, written in the
+// syntax of type annotations with type variables named a, b and so on.
+type Binding struct {
+	Name string
+	Type string
+}
+
+// Result is the outcome of checking a program.
+type Result struct {
+	// Errors holds the type errors found, ordered by position.
+	Errors []Error
+	// Bindings holds the top-level let bindings in source order.
+	Bindings []Binding
+}
+
+// operatorMethods maps the class methods that overload an operator to the
+// operator, as the evaluator does.
+var operatorMethods = map[string]string{
+	"__add__": "+",
+	"__sub__": "-",
+	"__mul__": "*",
+	"__div__": "/",
+	"__lt__":  "<",
+	"__gt__":  ">",
+}
+
+// Check infers the types of program and returns the type errors found.
+func Check(program *ast.Program) *Result {
+	resolved := resolver.Resolve(program)
+
+	c := &checker{
+		resolved:   resolved,
+		decls:      make(map[*ast.Identifier]*resolver.Symbol),
+		types:      make(map[*resolver.Symbol]*scheme),
+		mutated:    make(map[*resolver.Symbol]bool),
+		overloaded: make(map[string]bool),
+		result:     &Result{},
+	}
+	for _, symbol := range resolved.Symbols {
+		c.decls[symbol.Decl] = symbol
+	}
+	c.scan(program)
+
+	c.block(program.Statements)
+
+	for _, stmt := range program.Statements {
+		if export, ok := stmt.(*ast.ExportStatement); ok {
+			stmt = export.Statement
+		}
+		if let, ok := stmt.(*ast.LetStatement); ok {
+			c.result.Bindings = append(c.result.Bindings, Binding{
+				Name: let.Name.Value,
+				Type: newNamer().String(c.bindingType(let.Name)),
+			})
+		}
+	}
+
+	sort.SliceStable(c.result.Errors, func(i, j int) bool {
+		a, b := c.result.Errors[i], c.result.Errors[j]
+		return a.Line < b.Line || a.Line == b.Line && a.Column < b.Column
+	})
+
+	return c.result
+}
+
+type checker struct {
+	typer
+
+	resolved *resolver.Result
+	// decls maps the identifier declaring each symbol to it.
+	decls map[*ast.Identifier]*resolver.Symbol
+	// types holds the types of the symbols inferred so far.
+	types map[*resolver.Symbol]*scheme
+	// mutated holds the symbols assigned after their declaration, and those
+	// whose fields or elements are assigned.
+	mutated map[*resolver.Symbol]bool
+	// overloaded holds the operators a class may overload.
+	overloaded map[string]bool
+	// fn is the innermost function being inferred, nil at the top level.
+	fn     *function
+	result *Result
+}
+
+// function tracks the returns of a function being inferred.
+type function struct {
+	// ret is the return type, bound by the first return.
+	ret *typeVar
+	// annotation is the declared return type, if any.
+	annotation typ
+	// dynamic is set when returns differ in type.
+	dynamic   bool
+	generator bool
+}
+
+func (c *checker) report(tok token.Token, format string, args ...interface{}) {
+	c.result.Errors = append(c.result.Errors, Error{
+		Line:    tok.Line,
+		Column:  tok.Column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// mismatch reports that a value of type actual was found where expected
+// is needed, as "what must be expected, got actual".
+func (c *checker) mismatch(tok token.Token, what string, expected, actual typ) {
+	n := newNamer()
+	c.report(tok, "%s must be %s, got %s", what, n.String(expected), n.String(actual))
+}
+
+// scan finds the symbols that are assigned and the operators that classes
+// overload.
+func (c *checker) scan(program *ast.Program) {
+	ast.Inspect(program, func(node ast.Node) bool {
+		switch n := node.(type) {
+		case *ast.AssignExpression:
+			if ident, ok := assigned(n.Target).(*ast.Identifier); ok {
+				if symbol, ok := c.resolved.References[ident]; ok {
+					c.mutated[symbol] = true
+				}
+			}
+
+		case *ast.ClassStatement:
+			for _, method := range n.Methods {
+				if op, ok := operatorMethods[method.Name]; ok {
+					c.overloaded[op] = true
+				}
+			}
+
+		case *ast.ImportStatement:
+			for _, op := range operatorMethods {
+				c.overloaded[op] = true
+			}
+		}
+		return true
+	})
+}
+
+// assigned returns the variable an assignment to target changes: target
+// itself, or the value whose field or element it is.
+func assigned(target ast.Expression) ast.Expression {
+	for {
+		switch t := target.(type) {
+		case *ast.IndexExpression:
+			target = t.Left
+		case *ast.MemberExpression:
+			target = t.Object
+		default:
+			return target
+		}
+	}
+}
+
+// declare gives the symbol ident declares the type t, unless it is
+// assigned later.
+func (c *checker) declare(ident *ast.Identifier, t *scheme) {
+	symbol, ok := c.decls[ident]
+	if !ok || c.mutated[symbol] {
+		return
+	}
+	c.types[symbol] = t
+}
+
+func (c *checker) bindingType(ident *ast.Identifier) typ {
+	if s, ok := c.types[c.decls[ident]]; ok {
+		return s.t
+	}
+	return unknownType
+}
+
+// block infers the statements of a block and returns the type of the value
+// it produces. It reports false when the block always leaves through a
+// return or throw instead.
+func (c *checker) block(stmts []ast.Statement) (typ, bool) {
+	var value typ = unknownType
+	falls := true
+
+	for _, stmt := range stmts {
+		if stmt == nil {
+			continue
+		}
+		t, ok := c.statement(stmt)
+		if falls {
+			value, falls = t, ok
+		}
+	}
+
+	return value, falls
+}
+
+func (c *checker) statement(stmt ast.Statement) (typ, bool) {
+	switch s := stmt.(type) {
+	case *ast.LetStatement:
+		return c.let(s), true
+
+	case *ast.ReturnStatement:
+		c.returned(c.expression(s.ReturnValue), s.Token)
+		return unknownType, false
+
+	case *ast.ExpressionStatement:
+		if e, ok := s.Expression.(*ast.IfExpression); ok {
+			return c.ifExpression(e)
+		}
+		return c.expression(s.Expression), true
+
+	case *ast.BlockStatement:
+		return c.block(s.Statements)
+
+	case *ast.ClassStatement:
+		for _, method := range s.Methods {
+			c.function(method, nil)
+		}
+
+	case *ast.TryStatement:
+		c.block(s.Block.Statements)
+		if s.Catch != nil {
+			c.block(s.Catch.Statements)
+		}
+		if s.Finally != nil {
+			c.block(s.Finally.Statements)
+		}
+
+	case *ast.ThrowStatement:
+		c.expression(s.Value)
+		return unknownType, false
+
+	case *ast.DeferStatement:
+		c.expression(s.Expression)
+
+	case *ast.ExportStatement:
+		if s.Statement != nil {
+			return c.statement(s.Statement)
+		}
+
+	case *ast.YieldStatement:
+		c.expression(s.Value)
+
+	case *ast.ForStatement:
+		c.loopVariables(s.Key, s.Value, c.expression(s.Iterable), s.Token)
+		c.block(s.Body.Statements)
+	}
+
+	return unknownType, true
+}
+
+func (c *checker) let(s *ast.LetStatement) typ {
+	c.level++
+	var t typ
+	if lit, ok := s.Value.(*ast.FunctionLiteral); ok {
+		t = c.function(lit, s.Name)
+	} else {
+		t = c.expression(s.Value)
+	}
+	c.level--
+
+	if s.Type != nil {
+		annotation := c.annotation(s.Type)
+		if !c.unify(t, annotation) {
+			c.mismatch(s.Token, "variable "+s.Name.Value, annotation, t)
+		}
+		if !hasUnknown(annotation) {
+			t = annotation
+		}
+	}
+
+	c.declare(s.Name, c.generalize(t))
+	return t
+}
+
+// function infers the type of a function literal. name is the identifier
+// the literal is bound to by let, which its body may call, or nil.
+func (c *checker) function(lit *ast.FunctionLiteral, name *ast.Identifier) typ {
+	fn := &function{ret: c.newVar(), generator: lit.Generator}
+	if lit.ReturnType != nil {
+		fn.annotation = c.annotation(lit.ReturnType)
+	}
+
+	params := make([]typ, len(lit.Parameters))
+	for i, param := range lit.Parameters {
+		if lit.ParameterTypes != nil && lit.ParameterTypes[i] != nil {
+			params[i] = c.annotation(lit.ParameterTypes[i])
+		} else {
+			params[i] = c.newVar()
+		}
+		c.declare(param, &scheme{t: params[i]})
+	}
+
+	var ret typ = fn.ret
+	switch {
+	case fn.generator:
+		ret = generatorType
+	case fn.annotation != nil:
+		ret = fn.annotation
+	}
+	if name != nil {
+		c.declare(name, &scheme{t: fnOf(params, ret)})
+	}
+
+	outer := c.fn
+	c.fn = fn
+	if value, falls := c.block(lit.Body.Statements); falls {
+		c.returned(value, lit.Token)
+	}
+	c.fn = outer
+
+	if fn.dynamic {
+		ret = unknownType
+	}
+	return fnOf(params, ret)
+}
+
+// returned records that the current function returns a value of type t.
+func (c *checker) returned(t typ, tok token.Token) {
+	fn := c.fn
+	if fn == nil || fn.generator {
+		return
+	}
+
+	if fn.annotation != nil {
+		if !c.unify(t, fn.annotation) {
+			c.mismatch(tok, "return value", fn.annotation, t)
+		}
+		return
+	}
+
+	if resolve(fn.ret) == fn.ret {
+		c.unify(fn.ret, t)
+		return
+	}
+	if !same(join(fn.ret, t), fn.ret) {
+		fn.dynamic = true
+	}
+}
+
+func (c *checker) ifExpression(e *ast.IfExpression) (typ, bool) {
+	c.expression(e.Condition)
+
+	consequence, consequenceFalls := c.block(e.Consequence.Statements)
+	var alternative typ = nullType
+	alternativeFalls := true
+	if e.Alternative != nil {
+		alternative, alternativeFalls = c.block(e.Alternative.Statements)
+	}
+
+	switch {
+	case !consequenceFalls && !alternativeFalls:
+		return unknownType, false
+	case !consequenceFalls:
+		return alternative, true
+	case !alternativeFalls:
+		return consequence, true
+	}
+	return join(consequence, alternative), true
+}
+
+// loopVariables declares the variables of a for loop or comprehension over
+// a value of type iterable.
+func (c *checker) loopVariables(key, value *ast.Identifier, iterable typ, tok token.Token) {
+	var keyType, valueType typ = unknownType, unknownType
+
+	if t := concrete(iterable); t != nil {
+		switch t.name {
+		case "array":
+			keyType, valueType = intType, t.args[0]
+		case "string":
+			keyType, valueType = intType, stringType
+		case "range":
+			keyType, valueType = intType, intType
+		case "generator":
+			keyType = intType
+		case "hash":
+			if key == nil {
+				valueType = t.args[0]
+			} else {
+				keyType, valueType = t.args[0], t.args[1]
+			}
+		default:
+			c.report(tok, "cannot iterate over %s", newNamer().String(t))
+		}
+	}
+
+	if key != nil {
+		c.declare(key, &scheme{t: keyType})
+	}
+	c.declare(value, &scheme{t: valueType})
+}
+
+func (c *checker) expressions(exprs []ast.Expression) []typ {
+	types := make([]typ, len(exprs))
+	for i, expr := range exprs {
+		types[i] = c.expression(expr)
+	}
+	return types
+}
+
+func (c *checker) expression(expr ast.Expression) typ {
+	switch e := expr.(type) {
+	case *ast.IntegerLiteral:
+		return intType
+
+	case *ast.StringLiteral:
+		return stringType
+
+	case *ast.Boolean:
+		return boolType
+
+	case *ast.Identifier:
+		symbol, ok := c.resolved.References[e]
+		if !ok || c.mutated[symbol] {
+			return unknownType
+		}
+		if s, ok := c.types[symbol]; ok {
+			return c.instantiate(s)
+		}
+
+	case *ast.PrefixExpression:
+		right := c.expression(e.Right)
+		if e.Operator == "!" {
+			return boolType
+		}
+		if !c.unify(right, intType) {
+			c.report(e.Token, "unknown operator: -%s", newNamer().String(right))
+		}
+		return intType
+
+	case *ast.InfixExpression:
+		return c.infix(e)
+
+	case *ast.IfExpression:
+		t, _ := c.ifExpression(e)
+		return t
+
+	case *ast.FunctionLiteral:
+		return c.function(e, nil)
+
+	case *ast.CallExpression:
+		return c.call(e)
+
+	case *ast.ArrayLiteral:
+		elems := c.expressions(e.Elements)
+		if len(elems) == 0 {
+			return arrayOf(unknownType)
+		}
+		elem := elems[0]
+		for _, t := range elems[1:] {
+			elem = join(elem, t)
+		}
+		return arrayOf(elem)
+
+	case *ast.HashLiteral:
+		var key, value typ
+		for _, pair := range e.Pairs {
+			k, v := c.expression(pair.Key), c.expression(pair.Value)
+			if key == nil {
+				key, value = k, v
+			} else {
+				key, value = join(key, k), join(value, v)
+			}
+		}
+		if key == nil {
+			return hashOf(unknownType, unknownType)
+		}
+		return hashOf(key, value)
+
+	case *ast.IndexExpression:
+		return c.index(e)
+
+	case *ast.SliceExpression:
+		return c.slice(e)
+
+	case *ast.MemberExpression:
+		if t := concrete(c.expression(e.Object)); t != nil {
+			c.report(e.Token, "property access not supported: %s", newNamer().String(t))
+		}
+
+	case *ast.AssignExpression:
+		value := c.expression(e.Value)
+		switch target := e.Target.(type) {
+		case *ast.IndexExpression:
+			c.expression(target.Left)
+			c.expression(target.Index)
+		case *ast.MemberExpression:
+			c.expression(target.Object)
+		}
+		if e.Operator == "=" {
+			return value
+		}
+
+	case *ast.RangeExpression:
+		for _, bound := range []ast.Expression{e.Start, e.End} {
+			if t := c.expression(bound); !c.unify(t, intType) {
+				c.mismatch(e.Token, "range bounds", intType, t)
+			}
+		}
+		return rangeType
+
+	case *ast.ArrayComprehension:
+		c.clause(e.Clause)
+		return arrayOf(c.expression(e.Element))
+
+	case *ast.HashComprehension:
+		c.clause(e.Clause)
+		return hashOf(c.expression(e.Key), c.expression(e.Value))
+	}
+
+	return unknownType
+}
+
+func (c *checker) clause(clause *ast.ComprehensionClause) {
+	iterable := c.expression(clause.Iterable)
+	c.loopVariables(clause.Key, clause.Value, iterable, clause.Token)
+	if clause.Condition != nil {
+		c.expression(clause.Condition)
+	}
+}
+
+func (c *checker) infix(e *ast.InfixExpression) typ {
+	left := c.expression(e.Left)
+	right := c.expression(e.Right)
+
+	switch e.Operator {
+	case "==", "!=":
+		return boolType
+	case "in":
+		return c.in(e, left, right)
+	}
+
+	result := func(t typ) typ {
+		if e.Operator == "<" || e.Operator == ">" {
+			return boolType
+		}
+		return t
+	}
+
+	// An operand of unknown type may be an instance overloading the
+	// operator. Otherwise only int, and string for +, support it.
+	if _, ok := resolve(left).(*typeVar); ok {
+		if c.overloaded[e.Operator] {
+			return unknownType
+		}
+		if e.Operator != "+" {
+			c.unify(left, intType)
+		} else if r := concrete(right); r == nil || r.name == "int" || r.name == "string" {
+			c.unify(left, right)
+		}
+	}
+
+	l := concrete(left)
+	switch {
+	case l == nil:
+		if _, ok := resolve(left).(*typeVar); ok && e.Operator == "+" {
+			return left // int + int or string + string
+		}
+		return unknownType
+	case l.name == "int" || l.name == "string" && e.Operator == "+":
+		if !c.unify(right, l) {
+			c.badOperands(e, left, right)
+		}
+		return result(l)
+	}
+
+	c.badOperands(e, left, right)
+	return unknownType
+}
+
+// badOperands reports an infix operator applied to operands it does not
+// support, in the words of the evaluator.
+func (c *checker) badOperands(e *ast.InfixExpression, left, right typ) {
+	problem := "type mismatch"
+	if same(left, right) {
+		problem = "unknown operator"
+	}
+
+	n := newNamer()
+	c.report(e.Token, "%s: %s %s %s", problem, n.String(left), e.Operator, n.String(right))
+}
+
+func (c *checker) in(e *ast.InfixExpression, left, right typ) typ {
+	r := concrete(right)
+	switch {
+	case r == nil, r.name == "array", r.name == "hash", r.name == "range":
+	case r.name == "string":
+		if !c.unify(left, stringType) {
+			c.badOperands(e, left, right)
+		}
+	default:
+		n := newNamer()
+		c.report(e.Token, "unknown operator: %s in %s", n.String(left), n.String(right))
+	}
+	return boolType
+}
+
+func (c *checker) index(e *ast.IndexExpression) typ {
+	left := c.expression(e.Left)
+	index := c.expression(e.Index)
+
+	l := concrete(left)
+	if l == nil {
+		return unknownType
+	}
+
+	var elem typ
+	switch l.name {
+	case "array":
+		elem = l.args[0]
+	case "string":
+		elem = stringType
+	case "range":
+		elem = intType
+	case "hash":
+		return l.args[1]
+	default:
+		c.report(e.Token, "index operator not supported: %s", newNamer().String(l))
+		return unknownType
+	}
+
+	if !c.unify(index, intType) {
+		c.mismatch(e.Token, "index of "+newNamer().String(l), intType, index)
+	}
+	return elem
+}
+
+func (c *checker) slice(e *ast.SliceExpression) typ {
+	left := c.expression(e.Left)
+
+	for i, bound := range []ast.Expression{e.Start, e.End, e.Step} {
+		if bound == nil {
+			continue
+		}
+		what := "slice bounds"
+		if i == 2 {
+			what = "slice step"
+		}
+		if t := c.expression(bound); !c.unify(t, intType) {
+			c.mismatch(e.Token, what, intType, t)
+		}
+	}
+
+	l := concrete(left)
+	switch {
+	case l == nil:
+		return unknownType
+	case l.name == "array", l.name == "string":
+		return l
+	}
+
+	c.report(e.Token, "slice operator not supported: %s", newNamer().String(l))
+	return unknownType
+}
+
+func (c *checker) call(e *ast.CallExpression) typ {
+	switch e.Function.TokenLiteral() {
+	case "quote", "unquote":
+		return unknownType
+	}
+
+	if ident, ok := e.Function.(*ast.Identifier); ok {
+		if symbol, ok := c.resolved.References[ident]; ok && symbol.Kind == resolver.Builtin {
+			return c.builtinCall(e, ident.Value, c.expressions(e.Arguments))
+		}
+	}
+
+	callee := c.expression(e.Function)
+	args := c.expressions(e.Arguments)
+
+	f := concrete(callee)
+	if f == nil {
+		return unknownType
+	}
+	if f.name != "fn" {
+		c.report(e.Token, "not a function: %s", newNamer().String(f))
+		return unknownType
+	}
+
+	name := "function"
+	switch fn := e.Function.(type) {
+	case *ast.Identifier:
+		name = "`" + fn.Value + "`"
+	case *ast.MemberExpression:
+		name = "`" + fn.Property.Value + "`"
+	}
+
+	params, ret := f.args[:len(f.args)-1], f.args[len(f.args)-1]
+	if len(args) < len(params) {
+		c.report(e.Token, "wrong number of arguments to %s: got %d, want %d", name, len(args), len(params))
+	}
+	for i := 0; i < len(params) && i < len(args); i++ {
+		c.argument(e, name, i, len(params), args[i], params[i])
+	}
+
+	return ret
+}
+
+// argument checks the i-th of the n arguments of a call to the function
+// called name.
+func (c *checker) argument(e *ast.CallExpression, name string, i, n int, arg, param typ) {
+	if c.unify(arg, param) {
+		return
+	}
+
+	what := "argument to " + name
+	if n > 1 {
+		what = fmt.Sprintf("argument %d to %s", i+1, name)
+	}
+	c.mismatch(e.Token, what, param, arg)
+}
+
+// annotation returns the type a type annotation declares. Class and enum
+// names, fn and any are unknown.
+func (c *checker) annotation(a *ast.TypeAnnotation) typ {
+	switch {
+	case a.Key != nil:
+		return hashOf(c.annotation(a.Key), c.annotation(a.Elem))
+	case a.Elem != nil:
+		return arrayOf(c.annotation(a.Elem))
+	}
+
+	switch a.Name {
+	case "int":
+		return intType
+	case "string":
+		return stringType
+	case "bool":
+		return boolType
+	case "null":
+		return nullType
+	case "range":
+		return rangeType
+	case "generator":
+		return generatorType
+	case "array":
+		return arrayOf(unknownType)
+	case "hash":
+		return hashOf(unknownType, unknownType)
+	}
+	return unknownType
+}
+
+func hasUnknown(t typ) bool {
+	switch t := resolve(t).(type) {
+	case *unknown:
+		return true
+	case *con:
+		for _, arg := range t.args {
+			if hasUnknown(arg) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// builtinCall checks a call to the builtin function name and returns the
+// type of its result.
+func (c *checker) builtinCall(e *ast.CallExpression, name string, args []typ) typ {
+	builtin := evaluator.GetBuiltins()[name]
+	if !builtin.AcceptsArgs(len(args)) {
+		want := fmt.Sprint(builtin.MinArgs)
+		if builtin.MaxArgs != builtin.MinArgs {
+			want += fmt.Sprintf(" or %d", builtin.MaxArgs)
+		}
+		c.report(e.Token, "wrong number of arguments to `%s`: got %d, want %s", name, len(args), want)
+		return unknownType
+	}
+
+	// kinds checks that the argument is of one of the constructors names,
+	// for builtins that accept several types.
+	kinds := func(what string, names ...string) {
+		t := concrete(args[0])
+		if t == nil {
+			return
+		}
+		for _, n := range names {
+			if t.name == n {
+				return
+			}
+		}
+		c.report(e.Token, "argument to `%s` must be %s, got %s", name, what, newNamer().String(t))
+	}
+
+	var params []typ
+	var ret typ
+
+	switch name {
+	case "len":
+		kinds("string, array or range", "string", "array", "range")
+		return intType
+
+	case "variant":
+		kinds("an enum value")
+		return stringType
+
+	case "next", "close":
+		kinds("generator", "generator")
+		return unknownType
+
+	case "push":
+		elem := c.newVar()
+		c.argument(e, "`push`", 0, 2, args[0], arrayOf(elem))
+		return arrayOf(join(elem, args[1]))
+
+	case "first", "last", "pop":
+		elem := c.newVar()
+		params, ret = []typ{arrayOf(elem)}, elem
+
+	case "rest":
+		elem := c.newVar()
+		params, ret = []typ{arrayOf(elem)}, arrayOf(elem)
+
+	case "sum", "max", "min":
+		params, ret = []typ{arrayOf(intType)}, intType
+
+	case "join":
+		params, ret = []typ{arrayOf(c.newVar()), stringType}, stringType
+
+	case "split":
+		params, ret = []typ{stringType, stringType}, arrayOf(stringType)
+
+	case "upper", "lower":
+		params, ret = []typ{stringType}, stringType
+
+	case "error":
+		params, ret = []typ{stringType, stringType}, unknownType
+
+	case "freeze":
+		return args[0]
+
+	case "puts":
+		return nullType
+
+	default:
+		return unknownType
+	}
+
+	for i := 0; i < len(params) && i < len(args); i++ {
+		c.argument(e, "`"+name+"`", i, len(args), args[i], params[i])
+	}
+	return ret
+}
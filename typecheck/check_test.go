@@ -0,0 +1,144 @@
+package typecheck
+
+import (
+	"testing"
+
+	"github.com/Devashish08/InterPreter-Compiler/ast"
+	"github.com/Devashish08/InterPreter-Compiler/lexer"
+	"github.com/Devashish08/InterPreter-Compiler/parser"
+)
+
+func TestInferredTypes(t *testing.T) {
+	tests := []struct {
+		input    string
+		name     string
+		expected string
+	}{
+		{`let n = 1 + 2;`, "n", "int"},
+		{`let s = upper("a") + "b";`, "s", "string"},
+		{`let xs = [1, 2, 3];`, "xs", "[int]"},
+		{`let h = {"a": [true]};`, "h", "{string: [bool]}"},
+		{`let id = fn(x) { x };`, "id", "fn(a) -> a"},
+		{`let id = fn(x) { x }; let a = id(1); let b = id("s");`, "b", "string"},
+		{`let add = fn(a, b) { a + b };`, "add", "fn(a, a) -> a"},
+		{`let inc = fn(x) { x + 1 };`, "inc", "fn(int) -> int"},
+		{`let fib = fn(n) { if (n < 2) { return n; } fib(n - 1) + fib(n - 2) };`, "fib", "fn(int) -> int"},
+		{`let newAdder = fn(x) { fn(y) { x + y } };`, "newAdder", "fn(a) -> fn(a) -> a"},
+		{`let words = split("a b", " ");`, "words", "[string]"},
+		{`let total = fn(xs) { sum(xs) / len(xs) };`, "total", "fn([int]) -> int"},
+		{`let head = fn(xs) { first(xs) };`, "head", "fn([a]) -> a"},
+		{`let f = fn(x: int, y) -> int { return x * y; };`, "f", "fn(int, int) -> int"},
+		{`let squares = [x * x for x in 1..10];`, "squares", "[int]"},
+		{`let gen = fn() { yield 1; };`, "gen", "fn() -> generator"},
+		{`let mixed = [1, "a"];`, "mixed", "[any]"},
+		{`let pick = fn(c, a, b) { if (c) { a } else { b } };`, "pick", "fn(a, b, c) -> any"},
+		{`let n = 1; n = "a";`, "n", "any"},
+		{`class A {} let a = A();`, "a", "any"},
+	}
+
+	for _, tt := range tests {
+		result := Check(parse(t, tt.input))
+
+		found := false
+		for _, b := range result.Bindings {
+			if b.Name == tt.name {
+				found = true
+				if b.Type != tt.expected {
+					t.Errorf("wrong type for %s in %q. want=%s, got=%s", tt.name, tt.input, tt.expected, b.Type)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("no binding %s in %q", tt.name, tt.input)
+		}
+		if len(result.Errors) != 0 {
+			t.Errorf("unexpected errors for %q: %v", tt.input, result.Errors)
+		}
+	}
+}
+
+func TestTypeErrors(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{`len(5);`, "line 1, column 4: argument to `len` must be string, array or range, got int"},
+		{`"a" - 1;`, "line 1, column 5: type mismatch: string - int"},
+		{`"a" < "b";`, "line 1, column 5: unknown operator: string < string"},
+		{`-"a";`, "line 1, column 1: unknown operator: -string"},
+		{`first(fn(x) { x });`, "line 1, column 6: argument to `first` must be [a], got fn(b) -> b"},
+		{`let add = fn(a, b) { a + b }; add(1, "x");`, "line 1, column 34: argument 2 to `add` must be int, got string"},
+		{`let f = fn(xs) { len(xs) + first(xs) }; f("abc");`, "line 1, column 42: argument to `f` must be [int], got string"},
+		{`let f = fn(a, b) { a }; f(1);`, "line 1, column 26: wrong number of arguments to `f`: got 1, want 2"},
+		{`upper("a", "b");`, "line 1, column 6: wrong number of arguments to `upper`: got 2, want 1"},
+		{`sum(["a"]);`, "line 1, column 4: argument to `sum` must be [int], got [string]"},
+		{`let x = 5; x();`, "line 1, column 13: not a function: int"},
+		{`let x = 5; x.foo;`, "line 1, column 13: property access not supported: int"},
+		{`true[0];`, "line 1, column 5: index operator not supported: bool"},
+		{`[1, 2]["a"];`, "line 1, column 7: index of [int] must be int, got string"},
+		{`for x in 5 { x; }`, "line 1, column 1: cannot iterate over int"},
+		{`1 in 2;`, "line 1, column 3: unknown operator: int in int"},
+		{`"a"..3;`, "line 1, column 4: range bounds must be int, got string"},
+		{`let n: int = "s";`, "line 1, column 1: variable n must be int, got string"},
+		{`let f = fn() -> string { return 5; };`, "line 1, column 26: return value must be string, got int"},
+		{`let fact = fn(n) { if (n == 0) { 1 } else { n * fact(n - 1) } }; fact(5) + "a";`, "line 1, column 74: type mismatch: int + string"},
+		{`let f = fn() { "a" - 1 };`, "line 1, column 20: type mismatch: string - int"},
+	}
+
+	for _, tt := range tests {
+		result := Check(parse(t, tt.input))
+
+		if len(result.Errors) != 1 {
+			t.Errorf("wrong number of errors for %q. want 1, got %v", tt.input, result.Errors)
+			continue
+		}
+		if result.Errors[0].String() != tt.expected {
+			t.Errorf("wrong error for %q.\nwant=%s\ngot= %s", tt.input, tt.expected, result.Errors[0])
+		}
+	}
+}
+
+// TestDynamicPrograms checks that programs whose types cannot be inferred
+// are accepted.
+func TestDynamicPrograms(t *testing.T) {
+	inputs := []string{
+		// branches, elements and returns of different types
+		`let f = fn(x) { if (x) { 1 } else { "a" } }; f(true) + 1; upper(f(false));`,
+		`let xs = [1, "a", true]; let h = {"a": 1, 2: "b"}; upper(xs[1]); h[2] + "c";`,
+		`let f = fn(n) { if (n > 0) { return "positive"; } 0 }; len(f(1)); f(0) + 1;`,
+		`let pick = fn(a, b) { if (true) { return a; } b }; pick(1, "a");`,
+		// reassigned variables and mutated containers
+		`let n = 1; n = "a"; upper(n);`,
+		`let xs = [1]; xs[0] = "a"; upper(xs[0]);`,
+		`let f = fn(n) { n = "s" + n; n }; f("a");`,
+		// polymorphic functions
+		`let id = fn(x) { x }; id(1) + 1; upper(id("a"));`,
+		`let apply = fn(f, x) { f(x) }; apply(fn(x) { x + 1 }, 1); apply(upper, "a");`,
+		`let both = fn(f) { f(1); f("a") }; both(fn(x) { x });`,
+		// instances and overloaded operators
+		`class V { init(x) { self.x = x } __mul__(k) { V(self.x * k) } } let scale = fn(v) { v * 2 }; scale(V(1)).x;`,
+		`class P { init(x) { self.x = x } } let p = P(1); p.x + 1; p.x = "a"; upper(p.x);`,
+		`enum Shape { Circle(r) } let c = Shape.Circle(2); c.r * 2; variant(c);`,
+		// generators and errors
+		`let gen = fn(n) { for i in 0..n { yield i; } }; for x in gen(3) { puts(x + 1); } next(gen(1));`,
+		`try { throw error("x"); } catch (e) { puts(e.message); }`,
+	}
+
+	for _, input := range inputs {
+		result := Check(parse(t, input))
+		if len(result.Errors) != 0 {
+			t.Errorf("unexpected errors for %q: %v", input, result.Errors)
+		}
+	}
+}
+
+func parse(t *testing.T, input string) *ast.Program {
+	t.Helper()
+
+	p := parser.New(lexer.New(input))
+	program := p.ParseProgram()
+	if len(p.Errors()) != 0 {
+		t.Fatalf("parser errors for %q: %v", input, p.Errors())
+	}
+	return program
+}
@@ -0,0 +1,359 @@
+package typecheck
+
+import (
+	"strings"
+)
+
+// typ is an inferred type: a constructor applied to argument types, a type
+// variable, or the unknown type any.
+type typ interface {
+	isType()
+}
+
+// con is a type constructor with its arguments. The constructors are int,
+// string, bool, null, range and generator without arguments, array with the
+// element type, hash with the key and value types, and fn with the
+// parameter types followed by the return type.
+type con struct {
+	name string
+	args []typ
+}
+
+// typeVar stands for a type not inferred yet. Once unified with another
+// type it is bound to it. level is the depth of let bindings at which the
+// variable was created; only variables deeper than the binding being
+// generalized become polymorphic.
+type typeVar struct {
+	level int
+	bound typ
+}
+
+// unknown is the type of values the checker cannot follow, such as class
+// instances. It unifies with every type.
+type unknown struct{}
+
+func (*con) isType()     {}
+func (*typeVar) isType() {}
+func (*unknown) isType() {}
+
+var (
+	intType       = &con{name: "int"}
+	stringType    = &con{name: "string"}
+	boolType      = &con{name: "bool"}
+	nullType      = &con{name: "null"}
+	rangeType     = &con{name: "range"}
+	generatorType = &con{name: "generator"}
+	unknownType   = &unknown{}
+)
+
+func arrayOf(elem typ) *con { return &con{name: "array", args: []typ{elem}} }
+
+func hashOf(key, value typ) *con { return &con{name: "hash", args: []typ{key, value}} }
+
+func fnOf(params []typ, ret typ) *con {
+	args := make([]typ, 0, len(params)+1)
+	args = append(args, params...)
+	return &con{name: "fn", args: append(args, ret)}
+}
+
+// resolve follows the bindings of type variables.
+func resolve(t typ) typ {
+	for {
+		v, ok := t.(*typeVar)
+		if !ok || v.bound == nil {
+			return t
+		}
+		t = v.bound
+	}
+}
+
+// concrete returns t as a constructor, or nil when t is a type variable or
+// unknown.
+func concrete(t typ) *con {
+	c, _ := resolve(t).(*con)
+	return c
+}
+
+// same reports whether a and b are the same type.
+func same(a, b typ) bool {
+	a, b = resolve(a), resolve(b)
+	if a == b {
+		return true
+	}
+
+	ca, ok := a.(*con)
+	cb, ok2 := b.(*con)
+	if !ok || !ok2 || ca.name != cb.name || len(ca.args) != len(cb.args) {
+		return false
+	}
+	for i := range ca.args {
+		if !same(ca.args[i], cb.args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// join returns the type of a value that is either of type a or of type b,
+// such as the value of an if expression. Unlike unify it binds nothing:
+// where a and b differ the result is unknown.
+func join(a, b typ) typ {
+	a, b = resolve(a), resolve(b)
+	if a == b {
+		return a
+	}
+
+	ca, ok := a.(*con)
+	cb, ok2 := b.(*con)
+	if !ok || !ok2 || ca.name != cb.name || len(ca.args) != len(cb.args) {
+		return unknownType
+	}
+	if len(ca.args) == 0 {
+		return ca
+	}
+
+	args := make([]typ, len(ca.args))
+	for i := range ca.args {
+		args[i] = join(ca.args[i], cb.args[i])
+	}
+	return &con{name: ca.name, args: args}
+}
+
+// scheme is a type generalized over vars.
+type scheme struct {
+	vars []*typeVar
+	t    typ
+}
+
+// undo records the state of a type variable before unification changed it.
+type undo struct {
+	v     *typeVar
+	bound typ
+	level int
+}
+
+// typer creates, unifies and generalizes types.
+type typer struct {
+	// level is the number of let bindings whose value is being inferred.
+	level int
+	// trail records the changes of the unification in progress, to undo
+	// them when it fails.
+	trail []undo
+}
+
+func (ty *typer) newVar() *typeVar {
+	return &typeVar{level: ty.level}
+}
+
+// unify makes a and b the same type by binding type variables, and reports
+// whether it could. A failed unification binds nothing. The unknown type
+// unifies with every type, binding the type variables it meets to itself.
+func (ty *typer) unify(a, b typ) bool {
+	mark := len(ty.trail)
+	ok := ty.unifyTypes(a, b)
+	if !ok {
+		for i := len(ty.trail) - 1; i >= mark; i-- {
+			u := ty.trail[i]
+			u.v.bound, u.v.level = u.bound, u.level
+		}
+	}
+	ty.trail = ty.trail[:mark]
+	return ok
+}
+
+func (ty *typer) unifyTypes(a, b typ) bool {
+	a, b = resolve(a), resolve(b)
+	if a == b {
+		return true
+	}
+
+	if v, ok := a.(*typeVar); ok {
+		ty.bind(v, b)
+		return true
+	}
+	if v, ok := b.(*typeVar); ok {
+		ty.bind(v, a)
+		return true
+	}
+
+	ca, ok := a.(*con)
+	cb, ok2 := b.(*con)
+	if !ok || !ok2 {
+		return true // one of them is unknown
+	}
+	if ca.name != cb.name || len(ca.args) != len(cb.args) {
+		return false
+	}
+	for i := range ca.args {
+		if !ty.unifyTypes(ca.args[i], cb.args[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// bind binds v to t. The type variables of t move to v's level, so that
+// they are not generalized while v is not. A type that contains v, which
+// has no finite form, becomes unknown.
+func (ty *typer) bind(v *typeVar, t typ) {
+	if ty.occurs(v, t) {
+		t = unknownType
+	}
+	ty.trail = append(ty.trail, undo{v: v, level: v.level})
+	v.bound = t
+}
+
+// occurs reports whether v occurs in t, lowering the level of the other
+// variables of t to v's on the way.
+func (ty *typer) occurs(v *typeVar, t typ) bool {
+	switch t := resolve(t).(type) {
+	case *typeVar:
+		if t == v {
+			return true
+		}
+		if t.level > v.level {
+			ty.trail = append(ty.trail, undo{v: t, level: t.level})
+			t.level = v.level
+		}
+	case *con:
+		for _, arg := range t.args {
+			if ty.occurs(v, arg) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// generalize turns t into a scheme over the type variables created while
+// inferring the value of the current let binding that are still free.
+func (ty *typer) generalize(t typ) *scheme {
+	s := &scheme{t: t}
+	seen := make(map[*typeVar]bool)
+
+	var collect func(t typ)
+	collect = func(t typ) {
+		switch t := resolve(t).(type) {
+		case *typeVar:
+			if t.level > ty.level && !seen[t] {
+				seen[t] = true
+				s.vars = append(s.vars, t)
+			}
+		case *con:
+			for _, arg := range t.args {
+				collect(arg)
+			}
+		}
+	}
+	collect(t)
+
+	return s
+}
+
+// instantiate returns the type of s with fresh type variables in place of
+// the generalized ones.
+func (ty *typer) instantiate(s *scheme) typ {
+	if len(s.vars) == 0 {
+		return s.t
+	}
+
+	fresh := make(map[*typeVar]typ, len(s.vars))
+	for _, v := range s.vars {
+		fresh[v] = ty.newVar()
+	}
+
+	var copyType func(t typ) typ
+	copyType = func(t typ) typ {
+		switch t := resolve(t).(type) {
+		case *typeVar:
+			if f, ok := fresh[t]; ok {
+				return f
+			}
+			return t
+		case *con:
+			if len(t.args) == 0 {
+				return t
+			}
+			args := make([]typ, len(t.args))
+			for i, arg := range t.args {
+				args[i] = copyType(arg)
+			}
+			return &con{name: t.name, args: args}
+		}
+		return t
+	}
+
+	return copyType(s.t)
+}
+
+// namer names type variables a, b, c and so on in the order it meets them,
+// so that the types of one message share their names.
+type namer struct {
+	names map[*typeVar]string
+}
+
+func newNamer() *namer {
+	return &namer{names: make(map[*typeVar]string)}
+}
+
+// String renders t in the syntax of type annotations, with any for the
+// unknown type.
+func (n *namer) String(t typ) string {
+	var out strings.Builder
+	n.write(&out, t)
+	return out.String()
+}
+
+func (n *namer) write(out *strings.Builder, t typ) {
+	switch t := resolve(t).(type) {
+	case *unknown:
+		out.WriteString("any")
+
+	case *typeVar:
+		name, ok := n.names[t]
+		if !ok {
+			name = varName(len(n.names))
+			n.names[t] = name
+		}
+		out.WriteString(name)
+
+	case *con:
+		switch t.name {
+		case "array":
+			out.WriteString("[")
+			n.write(out, t.args[0])
+			out.WriteString("]")
+
+		case "hash":
+			out.WriteString("{")
+			n.write(out, t.args[0])
+			out.WriteString(": ")
+			n.write(out, t.args[1])
+			out.WriteString("}")
+
+		case "fn":
+			out.WriteString("fn(")
+			for i, param := range t.args[:len(t.args)-1] {
+				if i > 0 {
+					out.WriteString(", ")
+				}
+				n.write(out, param)
+			}
+			out.WriteString(") -> ")
+			n.write(out, t.args[len(t.args)-1])
+
+		default:
+			out.WriteString(t.name)
+		}
+	}
+}
+
+// varName returns the name of the i-th type variable: a to z, then a' to
+// z' and so on.
+func varName(i int) string {
+	name := string(rune('a' + i%26))
+	if i >= 26 {
+		name += strings.Repeat("'", i/26)
+	}
+	return name
+}